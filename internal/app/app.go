@@ -20,6 +20,10 @@ func Run(args []string) error {
 		return cli.RunInspect(cmdArgs)
 	case "validate":
 		return cli.RunValidate(cmdArgs)
+	case "rpmver":
+		return cli.RunRPMVer(cmdArgs)
+	case "templates":
+		return cli.RunTemplates(cmdArgs)
 	default:
 		return fmt.Errorf("unknown subcommand %q", subCommand)
 	}
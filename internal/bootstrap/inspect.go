@@ -1,8 +1,6 @@
 package bootstrap
 
 import (
-	"fmt"
-
 	"github.com/reservation-v/vlang/internal/inspect"
 )
 
@@ -12,13 +10,14 @@ type ProjectInfo struct {
 	ImportPath string `json:"import_path"`
 	Name       string `json:"name"`
 	HasVendor  bool   `json:"has_vendor"`
+	Workspace  string `json:"workspace,omitempty"`
 }
 
-func Inspect(dir string) (ProjectInfo, error) {
-	facts, err := inspect.Inspect(dir)
-	if err != nil {
-		return ProjectInfo{},
-			fmt.Errorf("failed to inspect %s: %w", dir, err)
+// NewProjectInfo picks the facts bootstrap reports about the project.
+func NewProjectInfo(facts inspect.Info) ProjectInfo {
+	var workspace string
+	if facts.Workspace != nil {
+		workspace = facts.Workspace.Path
 	}
 
 	return ProjectInfo{
@@ -27,6 +26,6 @@ func Inspect(dir string) (ProjectInfo, error) {
 		ImportPath: facts.ImportPath,
 		Name:       facts.Name,
 		HasVendor:  facts.HasVendor,
-	}, nil
-
+		Workspace:  workspace,
+	}
 }
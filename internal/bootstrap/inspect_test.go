@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/reservation-v/vlang/internal/inspect"
 )
 
 func writeGoMod(t *testing.T, dir, modulePath string) {
@@ -17,7 +19,7 @@ func writeGoMod(t *testing.T, dir, modulePath string) {
 	}
 }
 
-func TestInspect(t *testing.T) {
+func TestNewProjectInfo(t *testing.T) {
 	tests := []struct {
 		name       string
 		modulePath string
@@ -86,7 +88,7 @@ func TestInspect(t *testing.T) {
 				}
 			}
 
-			got, err := Inspect(dir)
+			facts, err := inspect.Inspect(dir)
 			if tc.wantErr {
 				if err == nil {
 					t.Fatalf("expected error, got nil")
@@ -96,6 +98,7 @@ func TestInspect(t *testing.T) {
 			if err != nil {
 				t.Fatalf("Inspect() error: %v", err)
 			}
+			got := NewProjectInfo(facts)
 
 			if got.Dir != dir {
 				t.Fatalf("Dir: got %q, want %q", got.Dir, dir)
@@ -0,0 +1,259 @@
+package bootstrap
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/reservation-v/vlang/internal/gosum"
+	"github.com/reservation-v/vlang/internal/modcache"
+)
+
+// ModCacheRoot is the top directory of a module cache tarball; the spec
+// points GOMODCACHE at it after unpacking.
+const ModCacheRoot = "modcache"
+
+// tarEpoch is the modification time of every tarball entry, so the same
+// modules always produce the same bytes.
+var tarEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type ModCacheResult struct {
+	Path         string `json:"path"`
+	Changed      bool   `json:"changed"`
+	Modules      int    `json:"modules"`
+	Files        int    `json:"files"`
+	Bytes        int64  `json:"bytes"`
+	GoModChanged bool   `json:"go_mod_changed"`
+	GoSumChanged bool   `json:"go_sum_changed"`
+}
+
+// ModCachePath is where bootstrap keeps the module cache tarball of the
+// package name.
+func ModCachePath(dir, name string) string {
+	return filepath.Join(dir, ".gear", name+"-modcache.tar")
+}
+
+// downloaded is one object of the go mod download -json stream.
+type downloaded struct {
+	Path    string
+	Version string
+	Error   string
+	Info    string
+	GoMod   string
+	Zip     string
+}
+
+// StageModCache runs go mod download in dir against staged copies of goMod
+// and go.sum and packs the cache/download entries a build with
+// GOFLAGS=-mod=mod GOPROXY=off needs into staging/<ModCacheRoot>.tar.
+// Staged.ModCache is that tarball.
+func StageModCache(ctx context.Context, dir, staging string, goMod []byte, opts VendorOptions) (Staged, ModCacheResult, error) {
+	staged, err := stageModFiles(dir, staging, goMod)
+	if err != nil {
+		return Staged{}, ModCacheResult{}, err
+	}
+
+	env, err := vendorEnv(os.Environ(), opts)
+	if err != nil {
+		return Staged{}, ModCacheResult{}, err
+	}
+
+	out, err := goOutput(ctx, dir, env, "env", "GOMODCACHE")
+	if err != nil {
+		return Staged{}, ModCacheResult{}, err
+	}
+	cache := strings.TrimSpace(string(out))
+	if cache == "" {
+		return Staged{}, ModCacheResult{}, fmt.Errorf("go env GOMODCACHE: empty")
+	}
+
+	// go mod download -json reports module errors on stdout and exits
+	// non-zero; those errors are more useful than the exit status.
+	out, runErr := goOutput(ctx, dir, env, "mod", "download", "-json", "-modfile="+staged.GoMod)
+
+	var result ModCacheResult
+	files := make(map[string]bool)
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var mod downloaded
+		if err := dec.Decode(&mod); err == io.EOF {
+			break
+		} else if err != nil {
+			if runErr != nil {
+				return Staged{}, ModCacheResult{}, runErr
+			}
+			return Staged{}, ModCacheResult{}, fmt.Errorf("go mod download: decode: %w", err)
+		}
+		if mod.Error != "" {
+			return Staged{}, ModCacheResult{}, fmt.Errorf("go mod download %s@%s: %s", mod.Path, mod.Version, mod.Error)
+		}
+		result.Modules++
+		for _, file := range []string{mod.Info, mod.GoMod, mod.Zip, mod.Zip + "hash"} {
+			if file != "" && file != "hash" && exists(file) {
+				files[file] = true
+			}
+		}
+	}
+
+	if runErr != nil {
+		return Staged{}, ModCacheResult{}, runErr
+	}
+
+	// The module graph also reads go.mod files of versions that are not
+	// selected; go.sum lists them.
+	sumData, _, err := readIfExists(staged.GoSum)
+	if err != nil {
+		return Staged{}, ModCacheResult{}, err
+	}
+	sums, err := gosum.Parse(sumData)
+	if err != nil {
+		return Staged{}, ModCacheResult{}, fmt.Errorf("parse go.sum: %w", err)
+	}
+	for _, line := range sums.Lines {
+		if !line.GoMod {
+			continue
+		}
+		base := filepath.Join(modcache.DownloadDir(cache, line.Path), modcache.Escape(line.Version))
+		for _, file := range []string{base + ".info", base + ".mod"} {
+			if exists(file) {
+				files[file] = true
+			}
+		}
+	}
+
+	staged.ModCache = filepath.Join(staging, ModCacheRoot+".tar")
+	result.Files, result.Bytes, err = writeModCacheTar(staged.ModCache, cache, files)
+	if err != nil {
+		return Staged{}, ModCacheResult{}, err
+	}
+
+	return staged, result, nil
+}
+
+// ModCache replaces the module cache tarball at path with a freshly
+// staged one and updates go.mod and go.sum alongside it, all or nothing.
+func ModCache(dir, path string, opts VendorOptions) (result ModCacheResult, err error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ModCacheResult{}, fmt.Errorf("read go.mod: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(dir, ".vlang-vendor-")
+	if err != nil {
+		return ModCacheResult{}, fmt.Errorf("create staging dir: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	staged, result, err := StageModCache(ctx, dir, staging, goMod, opts)
+	if err != nil {
+		return ModCacheResult{}, err
+	}
+	if ctx.Err() != nil {
+		return ModCacheResult{}, fmt.Errorf("modcache: interrupted")
+	}
+	result.Path = path
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return ModCacheResult{}, fmt.Errorf("create %s: %w", filepath.Dir(path), err)
+	}
+
+	var tx swapTx
+	defer func() {
+		if err != nil {
+			tx.rollback()
+		}
+	}()
+
+	if result.Changed, err = tx.swapFile(path, staged.ModCache, filepath.Join(staging, "modcache.tar.orig")); err != nil {
+		return ModCacheResult{}, err
+	}
+	if result.GoSumChanged, err = tx.swapFile(filepath.Join(dir, "go.sum"), staged.GoSum, filepath.Join(staging, "go.sum.orig")); err != nil {
+		return ModCacheResult{}, err
+	}
+	if result.GoModChanged, err = tx.swapFile(filepath.Join(dir, "go.mod"), staged.GoMod, filepath.Join(staging, "go.mod.orig")); err != nil {
+		return ModCacheResult{}, err
+	}
+
+	return result, nil
+}
+
+// writeModCacheTar packs files, given as paths inside cache, into a tar
+// at path under ModCacheRoot/. Entries are sorted and carry no owner or
+// time information.
+func writeModCacheTar(path, cache string, files map[string]bool) (int, int64, error) {
+	names := make([]string, 0, len(files))
+	for file := range files {
+		rel, err := filepath.Rel(cache, file)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return 0, 0, fmt.Errorf("%s is outside the module cache %s", file, cache)
+		}
+		names = append(names, filepath.ToSlash(rel))
+	}
+	sort.Strings(names)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	tw := tar.NewWriter(f)
+	var size int64
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(cache, filepath.FromSlash(name)))
+		if err != nil {
+			return 0, 0, fmt.Errorf("read module cache: %w", err)
+		}
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     ModCacheRoot + "/" + name,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+			ModTime:  tarEpoch,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return 0, 0, fmt.Errorf("write tar: %w", err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return 0, 0, fmt.Errorf("write tar: %w", err)
+		}
+		size += int64(len(data))
+	}
+	if err := tw.Close(); err != nil {
+		return 0, 0, fmt.Errorf("write tar: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, 0, fmt.Errorf("write %s: %w", path, err)
+	}
+	return len(names), size, nil
+}
+
+func goOutput(ctx context.Context, dir string, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return out, fmt.Errorf("go %s: interrupted", strings.Join(args[:2], " "))
+		}
+		return out, fmt.Errorf("go %s: %w", strings.Join(args[:2], " "), err)
+	}
+	return out, nil
+}
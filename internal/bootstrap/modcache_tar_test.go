@@ -0,0 +1,124 @@
+package bootstrap
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestModCacheTarball(t *testing.T) {
+	proxy := t.TempDir()
+	writeProxyModule(t, proxy, "example.com/Dep", "v1.0.0", map[string]string{
+		"go.mod": "module example.com/Dep\n\ngo 1.21\n",
+		"dep.go": "package dep\n\nconst X = 1\n",
+		"dep.h":  "#define X 1\n",
+	})
+
+	dir := t.TempDir()
+	writePlanFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.21\n\nrequire example.com/Dep v1.0.0\n")
+	writePlanFile(t, filepath.Join(dir, "main.go"), "package main\n\nimport _ \"example.com/Dep\"\n\nfunc main() {}\n")
+	writePlanFile(t, filepath.Join(dir, "go.sum"), proxyGoSum(t, proxy, "example.com/Dep", "v1.0.0"))
+
+	cache := t.TempDir()
+	t.Cleanup(func() { makeWritable(cache) })
+	opts := VendorOptions{Mode: VendorProxy, ProxyDir: proxy, ModCache: cache}
+	path := ModCachePath(dir, "app")
+
+	result, err := ModCache(dir, path, opts)
+	if err != nil {
+		t.Fatalf("ModCache() error: %v", err)
+	}
+	if !result.Changed || result.Modules != 1 || result.Path != path {
+		t.Fatalf("ModCache() result: %+v", result)
+	}
+	assertNoStaging(t, dir)
+
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read tarball: %v", err)
+	}
+	names := tarNames(t, path)
+	for _, want := range []string{
+		"modcache/cache/download/example.com/!dep/@v/v1.0.0.mod",
+		"modcache/cache/download/example.com/!dep/@v/v1.0.0.zip",
+	} {
+		if !strings.Contains(names, want+"\n") {
+			t.Errorf("tarball lacks %s:\n%s", want, names)
+		}
+	}
+
+	// Same inputs, same bytes.
+	result, err = ModCache(dir, path, opts)
+	if err != nil {
+		t.Fatalf("repeated ModCache() error: %v", err)
+	}
+	if result.Changed {
+		t.Errorf("repeated ModCache() changed the tarball")
+	}
+	if again, _ := os.ReadFile(path); string(again) != string(first) {
+		t.Errorf("tarball is not deterministic")
+	}
+
+	// The unpacked tarball alone is enough for an offline build.
+	build := t.TempDir()
+	t.Cleanup(func() { makeWritable(build) })
+	untar(t, path, build)
+	cmd := exec.Command("go", "build", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOSUMDB=off", "GOTOOLCHAIN=local",
+		"GOWORK=off", "GOMODCACHE="+filepath.Join(build, ModCacheRoot))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("offline build from tarball: %v\n%s", err, out)
+	}
+}
+
+func tarNames(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open tarball: %v", err)
+	}
+	defer f.Close()
+
+	var names strings.Builder
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names.String()
+		}
+		if err != nil {
+			t.Fatalf("read tarball: %v", err)
+		}
+		names.WriteString(hdr.Name + "\n")
+	}
+}
+
+func untar(t *testing.T, path, dir string) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open tarball: %v", err)
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("read tarball: %v", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("read %s: %v", hdr.Name, err)
+		}
+		writePlanFile(t, filepath.Join(dir, filepath.FromSlash(hdr.Name)), string(data))
+	}
+}
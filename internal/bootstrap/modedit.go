@@ -0,0 +1,141 @@
+package bootstrap
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/reservation-v/vlang/internal/modfile"
+)
+
+type ModEdits struct {
+	GoVersion   string
+	Toolchain   string
+	Replace     []string
+	DropReplace []string
+	Exclude     []string
+	DropExclude []string
+}
+
+func (m ModEdits) Empty() bool {
+	return m.GoVersion == "" && m.Toolchain == "" &&
+		len(m.Replace) == 0 && len(m.DropReplace) == 0 &&
+		len(m.Exclude) == 0 && len(m.DropExclude) == 0
+}
+
+// EditGoMod applies edits to dir/go.mod and reports whether the file changed.
+// Toolchain "none" removes the toolchain directive.
+func EditGoMod(dir string, edits ModEdits) (bool, error) {
+	if edits.Empty() {
+		return false, nil
+	}
+
+	data, editor, err := editGoMod(dir, edits)
+	if err != nil {
+		return false, err
+	}
+
+	if bytes.Equal(editor.Bytes(), data) {
+		return false, nil
+	}
+	if err := editor.WriteFile(filepath.Join(dir, "go.mod")); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// PlanGoMod returns dir/go.mod before and after edits without writing it.
+func PlanGoMod(dir string, edits ModEdits) (before, after []byte, err error) {
+	data, editor, err := editGoMod(dir, edits)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, editor.Bytes(), nil
+}
+
+func editGoMod(dir string, edits ModEdits) ([]byte, *modfile.Editor, error) {
+	data, readErr := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if readErr != nil {
+		return nil, nil, fmt.Errorf("read go.mod: %w", readErr)
+	}
+
+	editor, parseErr := modfile.NewEditor(data)
+	if parseErr != nil {
+		return nil, nil, fmt.Errorf("parse go.mod: %w", parseErr)
+	}
+
+	if err := applyModEdits(editor, edits); err != nil {
+		return nil, nil, err
+	}
+
+	return data, editor, nil
+}
+
+func applyModEdits(editor *modfile.Editor, edits ModEdits) error {
+	if edits.GoVersion != "" {
+		if err := editor.SetGo(edits.GoVersion); err != nil {
+			return fmt.Errorf("set go: %w", err)
+		}
+	}
+
+	switch edits.Toolchain {
+	case "":
+	case "none":
+		if err := editor.SetToolchain(""); err != nil {
+			return fmt.Errorf("drop toolchain: %w", err)
+		}
+	default:
+		if err := editor.SetToolchain(edits.Toolchain); err != nil {
+			return fmt.Errorf("set toolchain: %w", err)
+		}
+	}
+
+	for _, spec := range edits.DropReplace {
+		path, version := splitPathVersion(spec)
+		if err := editor.DropReplace(path, version); err != nil {
+			return fmt.Errorf("drop replace: %w", err)
+		}
+	}
+	for _, spec := range edits.Replace {
+		oldSpec, newSpec, ok := strings.Cut(spec, "=")
+		if !ok || oldSpec == "" || newSpec == "" {
+			return fmt.Errorf("replace %q: want old[@version]=new[@version]", spec)
+		}
+		oldPath, oldVersion := splitPathVersion(oldSpec)
+		newPath, newVersion := splitPathVersion(newSpec)
+		if err := editor.AddReplace(oldPath, oldVersion, newPath, newVersion); err != nil {
+			return fmt.Errorf("replace: %w", err)
+		}
+	}
+
+	for _, spec := range edits.DropExclude {
+		path, version := splitPathVersion(spec)
+		if err := editor.DropExclude(path, version); err != nil {
+			return fmt.Errorf("drop exclude: %w", err)
+		}
+	}
+	for _, spec := range edits.Exclude {
+		path, version := splitPathVersion(spec)
+		if version == "" {
+			return fmt.Errorf("exclude %q: want path@version", spec)
+		}
+		if err := editor.AddExclude(path, version); err != nil {
+			return fmt.Errorf("exclude: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func splitPathVersion(spec string) (path, version string) {
+	if modfile.IsDirectoryPath(spec) {
+		return spec, ""
+	}
+	if idx := strings.LastIndex(spec, "@"); idx > 0 {
+		return spec[:idx], spec[idx+1:]
+	}
+	return spec, ""
+}
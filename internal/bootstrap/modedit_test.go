@@ -0,0 +1,71 @@
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEditGoMod(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "go.mod")
+	in := "module example.com/app\n\ngo 1.25\n\nrequire example.com/dep v1.0.0\n"
+	if err := os.WriteFile(path, []byte(in), 0o644); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+
+	changed, err := EditGoMod(dir, ModEdits{
+		GoVersion: "1.22",
+		Replace:   []string{"example.com/dep=example.com/fork@v1.0.1", "example.com/local=../local"},
+		Exclude:   []string{"example.com/dep@v0.9.0"},
+	})
+	if err != nil {
+		t.Fatalf("EditGoMod() error: %v", err)
+	}
+	if !changed {
+		t.Fatalf("EditGoMod() changed: got false, want true")
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read go.mod: %v", err)
+	}
+	want := "module example.com/app\n\ngo 1.22\n\nrequire example.com/dep v1.0.0\n\n" +
+		"replace example.com/dep => example.com/fork v1.0.1\n" +
+		"replace example.com/local => ../local\n\n" +
+		"exclude example.com/dep v0.9.0\n"
+	if string(got) != want {
+		t.Fatalf("go.mod:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	changed, err = EditGoMod(dir, ModEdits{GoVersion: "1.22"})
+	if err != nil {
+		t.Fatalf("EditGoMod() second run error: %v", err)
+	}
+	if changed {
+		t.Fatalf("EditGoMod() second run changed: got true, want false")
+	}
+}
+
+func TestEditGoModErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		edits ModEdits
+	}{
+		{name: "bad_replace_spec", edits: ModEdits{Replace: []string{"example.com/dep"}}},
+		{name: "exclude_without_version", edits: ModEdits{Exclude: []string{"example.com/dep"}}},
+		{name: "drop_missing_replace", edits: ModEdits{DropReplace: []string{"example.com/dep"}}},
+		{name: "bad_go_version", edits: ModEdits{GoVersion: "go1.22"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeGoMod(t, dir, "example.com/app")
+
+			if _, err := EditGoMod(dir, tc.edits); err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package bootstrap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/reservation-v/vlang/internal/diff"
+)
+
+type ChangeKind string
+
+const (
+	ChangeCreated  ChangeKind = "created"
+	ChangeModified ChangeKind = "modified"
+	ChangeDeleted  ChangeKind = "deleted"
+)
+
+type Change struct {
+	Path string     `json:"path"`
+	Kind ChangeKind `json:"kind"`
+	Diff string     `json:"diff,omitempty"`
+}
+
+// Plan collects what bootstrap would change in Dir. Paths are relative to
+// Dir and use forward slashes.
+type Plan struct {
+	Dir     string        `json:"dir"`
+	Changes []Change      `json:"changes"`
+	Vendor  *VendorReport `json:"vendor,omitempty"`
+	Prune   *PruneResult  `json:"prune,omitempty"`
+}
+
+func NewPlan(dir string) *Plan {
+	return &Plan{Dir: dir, Changes: []Change{}}
+}
+
+// File records rel being written with content.
+func (p *Plan) File(rel string, content []byte) error {
+	old, exists, err := readIfExists(filepath.Join(p.Dir, filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+	p.add(rel, old, exists, content, true)
+	return nil
+}
+
+// Tree records directory rel being replaced by the contents of staged.
+// A missing staged directory means rel is removed.
+func (p *Plan) Tree(rel, staged string) error {
+	oldFiles, err := treeFiles(filepath.Join(p.Dir, filepath.FromSlash(rel)))
+	if err != nil {
+		return err
+	}
+	newFiles, err := treeFiles(staged)
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]bool, len(oldFiles)+len(newFiles))
+	for name := range oldFiles {
+		names[name] = true
+	}
+	for name := range newFiles {
+		names[name] = true
+	}
+
+	for name := range names {
+		path := rel + "/" + name
+		var oldData, newData []byte
+		_, oldExists := oldFiles[name]
+		_, newExists := newFiles[name]
+		if oldExists {
+			if oldData, err = os.ReadFile(oldFiles[name]); err != nil {
+				return fmt.Errorf("read %s: %w", path, err)
+			}
+		}
+		if newExists {
+			if newData, err = os.ReadFile(newFiles[name]); err != nil {
+				return fmt.Errorf("read staged %s: %w", path, err)
+			}
+		}
+		p.add(path, oldData, oldExists, newData, newExists)
+	}
+
+	return nil
+}
+
+// VendorModules records the module and license changes between vendor/
+// and the staged vendor tree.
+func (p *Plan) VendorModules(staged string) error {
+	report, err := DiffVendor(filepath.Join(p.Dir, "vendor"), staged)
+	if err != nil {
+		return err
+	}
+	p.Vendor = &report
+	return nil
+}
+
+func (p *Plan) Sort() {
+	sort.Slice(p.Changes, func(i, j int) bool { return p.Changes[i].Path < p.Changes[j].Path })
+}
+
+func (p *Plan) add(rel string, old []byte, oldExists bool, content []byte, newExists bool) {
+	var kind ChangeKind
+	switch {
+	case !oldExists && !newExists:
+		return
+	case !oldExists:
+		kind = ChangeCreated
+	case !newExists:
+		kind = ChangeDeleted
+	case bytes.Equal(old, content):
+		return
+	default:
+		kind = ChangeModified
+	}
+
+	oldName, newName := "a/"+rel, "b/"+rel
+	if !oldExists {
+		oldName = "/dev/null"
+	}
+	if !newExists {
+		newName = "/dev/null"
+	}
+
+	var text string
+	if bytes.IndexByte(old, 0) >= 0 || bytes.IndexByte(content, 0) >= 0 {
+		text = fmt.Sprintf("Binary files %s and %s differ\n", oldName, newName)
+	} else {
+		text = diff.Unified(oldName, newName, old, content)
+	}
+
+	p.Changes = append(p.Changes, Change{Path: rel, Kind: kind, Diff: text})
+}
+
+func readIfExists(path string) ([]byte, bool, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return data, true, nil
+	}
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	return nil, false, fmt.Errorf("read %s: %w", path, err)
+}
+
+// treeFiles maps slash-separated paths relative to root to file paths.
+func treeFiles(root string) (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return relErr
+		}
+		files[filepath.ToSlash(rel)] = path
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", root, err)
+	}
+	return files, nil
+}
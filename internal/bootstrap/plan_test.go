@@ -0,0 +1,99 @@
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlanFilesAndTree(t *testing.T) {
+	dir := t.TempDir()
+	staged := t.TempDir()
+
+	writePlanFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.21\n")
+	writePlanFile(t, filepath.Join(dir, "vendor", "modules.txt"), "# example.com/old v1.0.0\nexample.com/old\n")
+	writePlanFile(t, filepath.Join(dir, "vendor", "example.com", "old", "old.go"), "package old\n")
+	writePlanFile(t, filepath.Join(dir, "vendor", "example.com", "keep", "keep.go"), "package keep\n")
+	writePlanFile(t, filepath.Join(staged, "modules.txt"), "# example.com/new v1.1.0\nexample.com/new\n")
+	writePlanFile(t, filepath.Join(staged, "example.com", "new", "new.bin"), "\x00\x01")
+	writePlanFile(t, filepath.Join(staged, "example.com", "keep", "keep.go"), "package keep\n")
+
+	plan := NewPlan(dir)
+	if err := plan.File("go.mod", []byte("module example.com/app\n\ngo 1.22\n")); err != nil {
+		t.Fatalf("File(go.mod) error: %v", err)
+	}
+	if err := plan.File("go.sum", []byte("example.com/new v1.1.0 h1:x=\n")); err != nil {
+		t.Fatalf("File(go.sum) error: %v", err)
+	}
+	if err := plan.Tree("vendor", staged); err != nil {
+		t.Fatalf("Tree() error: %v", err)
+	}
+	if err := plan.VendorModules(staged); err != nil {
+		t.Fatalf("VendorModules() error: %v", err)
+	}
+	plan.Sort()
+
+	want := []struct {
+		path string
+		kind ChangeKind
+	}{
+		{path: "go.mod", kind: ChangeModified},
+		{path: "go.sum", kind: ChangeCreated},
+		{path: "vendor/example.com/new/new.bin", kind: ChangeCreated},
+		{path: "vendor/example.com/old/old.go", kind: ChangeDeleted},
+		{path: "vendor/modules.txt", kind: ChangeModified},
+	}
+	if len(plan.Changes) != len(want) {
+		t.Fatalf("Changes: got %+v, want %d entries", plan.Changes, len(want))
+	}
+	for i, w := range want {
+		if got := plan.Changes[i]; got.Path != w.path || got.Kind != w.kind {
+			t.Errorf("Changes[%d]: got %s %s, want %s %s", i, got.Kind, got.Path, w.kind, w.path)
+		}
+	}
+
+	if diff := plan.Changes[0].Diff; !strings.Contains(diff, "-go 1.21\n+go 1.22\n") {
+		t.Errorf("go.mod diff:\n%s", diff)
+	}
+	if diff := plan.Changes[2].Diff; diff != "Binary files /dev/null and b/vendor/example.com/new/new.bin differ\n" {
+		t.Errorf("binary diff: got %q", diff)
+	}
+	if diff := plan.Changes[3].Diff; !strings.HasPrefix(diff, "--- a/vendor/example.com/old/old.go\n+++ /dev/null\n") {
+		t.Errorf("deleted diff:\n%s", diff)
+	}
+
+	if plan.Vendor == nil || len(plan.Vendor.Added) != 1 || plan.Vendor.Added[0].Path != "example.com/new" {
+		t.Errorf("Vendor.Added: got %+v", plan.Vendor)
+	}
+	if plan.Vendor == nil || len(plan.Vendor.Removed) != 1 || plan.Vendor.Removed[0].Old != "v1.0.0" {
+		t.Errorf("Vendor.Removed: got %+v", plan.Vendor)
+	}
+}
+
+func TestPlanGoModLeavesFile(t *testing.T) {
+	dir := t.TempDir()
+	in := "module example.com/app\n\ngo 1.25\n"
+	writePlanFile(t, filepath.Join(dir, "go.mod"), in)
+
+	before, after, err := PlanGoMod(dir, ModEdits{GoVersion: "1.22"})
+	if err != nil {
+		t.Fatalf("PlanGoMod() error: %v", err)
+	}
+	if string(before) != in || string(after) != "module example.com/app\n\ngo 1.22\n" {
+		t.Fatalf("PlanGoMod(): got %q -> %q", before, after)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "go.mod")); string(data) != in {
+		t.Fatalf("PlanGoMod() modified go.mod:\n%s", data)
+	}
+}
+
+func writePlanFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
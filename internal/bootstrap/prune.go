@@ -0,0 +1,262 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"go/build"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/reservation-v/vlang/internal/license"
+	"github.com/reservation-v/vlang/internal/modulestxt"
+)
+
+// DefaultPruneDeny is always applied when pruning; PruneOptions.Deny adds
+// to it.
+var DefaultPruneDeny = []string{
+	"*.md", "*.markdown", "*.rst", "*.adoc",
+	"doc", "docs", "example", "examples", "_examples", "testdata",
+	"*.png", "*.jpg", "*.jpeg", "*.gif", "*.svg",
+	".github", ".gitignore", ".travis.yml", ".golangci.yml", ".golangci.yaml",
+	"Makefile", "Dockerfile",
+}
+
+// PruneOptions selects the files dropped from a vendor tree. A pattern
+// without a slash matches any path element ("docs", "*.md"); one with a
+// slash matches the path relative to vendor/ or one of its parents.
+// Allow wins over Deny. Sources of vendored packages, their embedded
+// files, license files and modules.txt are never removed.
+type PruneOptions struct {
+	Allow []string
+	Deny  []string
+}
+
+type PruneResult struct {
+	FilesBefore int   `json:"files_before"`
+	FilesAfter  int   `json:"files_after"`
+	BytesBefore int64 `json:"bytes_before"`
+	BytesAfter  int64 `json:"bytes_after"`
+}
+
+// buildExts are the file kinds the go command may compile into a package.
+var buildExts = []string{
+	".go", ".c", ".cc", ".cpp", ".cxx", ".h", ".hh", ".hpp", ".hxx",
+	".m", ".s", ".S", ".sx", ".f", ".F", ".for", ".f90", ".syso", ".swig", ".swigcxx",
+}
+
+// Prune removes files that are not needed to build the packages listed in
+// vendorDir/modules.txt and checks that go/build sees the same packages
+// afterwards.
+func Prune(vendorDir string, opts PruneOptions) (PruneResult, error) {
+	data, _, err := readIfExists(filepath.Join(vendorDir, "modules.txt"))
+	if err != nil {
+		return PruneResult{}, err
+	}
+	vendored, err := modulestxt.Parse(data)
+	if err != nil {
+		return PruneResult{}, fmt.Errorf("parse modules.txt: %w", err)
+	}
+	var packages []string
+	for _, mod := range vendored.Modules {
+		packages = append(packages, mod.Packages...)
+	}
+
+	before := packageFacts(vendorDir, packages)
+	keep, err := keptFiles(vendorDir, packages)
+	if err != nil {
+		return PruneResult{}, err
+	}
+
+	files, err := treeFiles(vendorDir)
+	if err != nil {
+		return PruneResult{}, err
+	}
+	deny := append(slices.Clone(DefaultPruneDeny), opts.Deny...)
+
+	var result PruneResult
+	for rel, file := range files {
+		info, err := os.Lstat(file)
+		if err != nil {
+			return PruneResult{}, fmt.Errorf("stat %s: %w", file, err)
+		}
+		result.FilesBefore++
+		result.BytesBefore += info.Size()
+
+		if keep[rel] || rel == "modules.txt" || license.IsLicenseFile(path.Base(rel)) ||
+			!matchAny(deny, rel) || matchAny(opts.Allow, rel) {
+			result.FilesAfter++
+			result.BytesAfter += info.Size()
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			return PruneResult{}, fmt.Errorf("prune %s: %w", file, err)
+		}
+		removeEmptyParents(vendorDir, filepath.Dir(file))
+	}
+
+	after := packageFacts(vendorDir, packages)
+	for _, pkg := range packages {
+		if before[pkg] != after[pkg] {
+			return PruneResult{}, fmt.Errorf("pruning changed package %s: %s -> %s", pkg, before[pkg], after[pkg])
+		}
+	}
+
+	return result, nil
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(strings.TrimSuffix(pattern, "/"), rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPattern(pattern, rel string) bool {
+	elems := strings.Split(rel, "/")
+	if !strings.Contains(pattern, "/") {
+		for _, elem := range elems {
+			if ok, _ := path.Match(pattern, elem); ok {
+				return true
+			}
+		}
+		return false
+	}
+	for i := range elems {
+		if ok, _ := path.Match(pattern, strings.Join(elems[:i+1], "/")); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// keptFiles lists the files of the vendored packages the go command may
+// read: sources for any platform, and files matched by //go:embed.
+func keptFiles(vendorDir string, packages []string) (map[string]bool, error) {
+	keep := make(map[string]bool)
+	for _, pkg := range packages {
+		pkgDir := filepath.Join(vendorDir, filepath.FromSlash(pkg))
+		entries, err := os.ReadDir(pkgDir)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("read %s: %w", pkgDir, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !slices.Contains(buildExts, filepath.Ext(entry.Name())) {
+				continue
+			}
+			keep[pkg+"/"+entry.Name()] = true
+			if filepath.Ext(entry.Name()) != ".go" {
+				continue
+			}
+			patterns, err := embedPatterns(filepath.Join(pkgDir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			if err := keepEmbedded(keep, vendorDir, pkgDir, patterns); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return keep, nil
+}
+
+// embedPatterns returns the arguments of the //go:embed lines in file.
+func embedPatterns(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", file, err)
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		args, ok := strings.CutPrefix(strings.TrimSpace(line), "//go:embed")
+		if !ok || (args != "" && args[0] != ' ' && args[0] != '\t') {
+			continue
+		}
+		for args = strings.TrimSpace(args); args != ""; args = strings.TrimSpace(args) {
+			var arg string
+			if args[0] == '"' || args[0] == '`' {
+				end := strings.IndexByte(args[1:], args[0])
+				if end < 0 {
+					return nil, fmt.Errorf("%s: unterminated //go:embed pattern", file)
+				}
+				arg, err = strconv.Unquote(args[:end+2])
+				if err != nil {
+					return nil, fmt.Errorf("%s: //go:embed pattern: %w", file, err)
+				}
+				args = args[end+2:]
+			} else {
+				arg, args, _ = strings.Cut(args, " ")
+			}
+			patterns = append(patterns, strings.TrimPrefix(arg, "all:"))
+		}
+	}
+	return patterns, nil
+}
+
+func keepEmbedded(keep map[string]bool, vendorDir, pkgDir string, patterns []string) error {
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(pkgDir, filepath.FromSlash(pattern)))
+		if err != nil {
+			return fmt.Errorf("//go:embed %s in %s: %w", pattern, pkgDir, err)
+		}
+		for _, match := range matches {
+			files, err := treeFiles(match)
+			if err != nil {
+				return err
+			}
+			for name := range files {
+				rel, err := filepath.Rel(vendorDir, filepath.Join(match, filepath.FromSlash(name)))
+				if err != nil {
+					return err
+				}
+				keep[filepath.ToSlash(rel)] = true
+			}
+		}
+	}
+	return nil
+}
+
+// packageFacts summarizes what go/build finds in each vendored package for
+// the current platform, including whether its embed patterns still match.
+func packageFacts(vendorDir string, packages []string) map[string]string {
+	facts := make(map[string]string, len(packages))
+	for _, pkg := range packages {
+		pkgDir := filepath.Join(vendorDir, filepath.FromSlash(pkg))
+		p, err := build.Default.ImportDir(pkgDir, 0)
+		if err != nil {
+			facts[pkg] = "error: " + err.Error()
+			continue
+		}
+
+		var b strings.Builder
+		for _, list := range [][]string{p.GoFiles, p.CgoFiles, p.CFiles, p.CXXFiles, p.HFiles, p.SFiles, p.SysoFiles, p.EmbedPatterns} {
+			b.WriteString(strings.Join(list, ","))
+			b.WriteString(";")
+		}
+		for _, pattern := range p.EmbedPatterns {
+			matches, _ := filepath.Glob(filepath.Join(pkgDir, filepath.FromSlash(strings.TrimPrefix(pattern, "all:"))))
+			fmt.Fprintf(&b, "%s=%d;", pattern, len(matches))
+		}
+		facts[pkg] = b.String()
+	}
+	return facts
+}
+
+func removeEmptyParents(root, dir string) {
+	for dir != root && strings.HasPrefix(dir, root) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,116 @@
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrune(t *testing.T) {
+	vendor := t.TempDir()
+	files := map[string]string{
+		"modules.txt":                           "# example.com/dep v1.0.0\n## explicit\nexample.com/dep\nexample.com/dep/sub\n",
+		"example.com/dep/dep.go":                "package dep\n\nimport _ \"embed\"\n\n//go:embed testdata/schema.json \"docs/a b.txt\"\nvar schema string\n",
+		"example.com/dep/dep_linux.s":           "TEXT ·f(SB),0,$0\n",
+		"example.com/dep/README.md":             "# dep\n",
+		"example.com/dep/LICENSE.md":            "MIT\n",
+		"example.com/dep/testdata/schema.json":  "{}\n",
+		"example.com/dep/testdata/other.json":   "{}\n",
+		"example.com/dep/docs/a b.txt":          "embedded\n",
+		"example.com/dep/docs/guide.txt":        "guide\n",
+		"example.com/dep/examples/main.go":      "package main\n",
+		"example.com/dep/sub/sub.go":            "package sub\n",
+		"example.com/dep/sub/logo.png":          "\x89PNG",
+		"example.com/dep/sub/CHANGES.rst":       "changes\n",
+		"example.com/dep/sub/keep/notes.md":     "allowed\n",
+		"example.com/dep/sub/data.bin":          "denied\n",
+		"example.com/dep/.github/workflows/x.y": "ci\n",
+	}
+	var total int64
+	for name, content := range files {
+		writePlanFile(t, filepath.Join(vendor, filepath.FromSlash(name)), content)
+		total += int64(len(content))
+	}
+
+	result, err := Prune(vendor, PruneOptions{
+		Allow: []string{"example.com/dep/sub/keep"},
+		Deny:  []string{"*.bin"},
+	})
+	if err != nil {
+		t.Fatalf("Prune() error: %v", err)
+	}
+
+	removed := []string{
+		"example.com/dep/README.md",
+		"example.com/dep/testdata/other.json",
+		"example.com/dep/docs/guide.txt",
+		"example.com/dep/examples/main.go",
+		"example.com/dep/sub/logo.png",
+		"example.com/dep/sub/CHANGES.rst",
+		"example.com/dep/sub/data.bin",
+		"example.com/dep/.github/workflows/x.y",
+	}
+	var removedBytes int64
+	for name, content := range files {
+		_, err := os.Stat(filepath.Join(vendor, filepath.FromSlash(name)))
+		wantRemoved := false
+		for _, r := range removed {
+			wantRemoved = wantRemoved || r == name
+		}
+		if wantRemoved {
+			removedBytes += int64(len(content))
+		}
+		if gotRemoved := os.IsNotExist(err); gotRemoved != wantRemoved {
+			t.Errorf("%s: removed %t, want %t", name, gotRemoved, wantRemoved)
+		}
+	}
+	for _, dir := range []string{"example.com/dep/examples", "example.com/dep/.github"} {
+		if _, err := os.Stat(filepath.Join(vendor, dir)); !os.IsNotExist(err) {
+			t.Errorf("empty directory %s left behind", dir)
+		}
+	}
+
+	want := PruneResult{
+		FilesBefore: len(files),
+		FilesAfter:  len(files) - len(removed),
+		BytesBefore: total,
+		BytesAfter:  total - removedBytes,
+	}
+	if result != want {
+		t.Errorf("Prune() result: got %+v, want %+v", result, want)
+	}
+}
+
+func TestEmbedPatterns(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "x.go")
+	writePlanFile(t, file, "package x\n\n//go:embed a.txt  \"b c.txt\"\n//go:embed `all:static`\n//go:embedded nope\n")
+
+	got, err := embedPatterns(file)
+	if err != nil {
+		t.Fatalf("embedPatterns() error: %v", err)
+	}
+	if strings.Join(got, "|") != "a.txt|b c.txt|static" {
+		t.Errorf("embedPatterns(): got %q", got)
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{pattern: "*.md", rel: "example.com/a/README.md", want: true},
+		{pattern: "docs", rel: "example.com/a/docs/x.txt", want: true},
+		{pattern: "docs", rel: "example.com/a/mydocs/x.txt", want: false},
+		{pattern: "example.com/a/docs", rel: "example.com/a/docs/x.txt", want: true},
+		{pattern: "example.com/*/x.txt", rel: "example.com/a/x.txt", want: true},
+		{pattern: "example.com/a", rel: "example.com/ab/x.txt", want: false},
+	}
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) = %t, want %t", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
@@ -1,44 +1,382 @@
 package bootstrap
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/reservation-v/vlang/internal/modcache"
 )
 
-func Vendor(dir string) (bool, error) {
-	cmd := exec.Command("go", "mod", "vendor")
-	cmd.Dir = dir
+type VendorMode string
+
+const (
+	// VendorOnline keeps the ambient GOPROXY and checksum database settings.
+	VendorOnline VendorMode = "online"
+	// VendorModCache resolves modules only from ModCache (or the default
+	// module cache) with GOPROXY=off.
+	VendorModCache VendorMode = "modcache"
+	// VendorProxy downloads from the file:// GOPROXY directory ProxyDir.
+	VendorProxy VendorMode = "proxy"
+	// VendorOff is GOPROXY=off against the ambient module cache.
+	VendorOff VendorMode = "off"
+)
+
+type VendorOptions struct {
+	GoWorkOff bool
+	Mode      VendorMode
+	ModCache  string
+	ProxyDir  string
+	// Prune, when set, prunes the staged vendor tree before it is applied.
+	Prune *PruneOptions
+}
+
+// controlledEnv lists the go environment variables vlang sets itself
+// instead of inheriting.
+var controlledEnv = []string{
+	"GOFLAGS", "GOWORK", "GOPROXY", "GONOPROXY", "GOPRIVATE",
+	"GOSUMDB", "GONOSUMDB", "GONOSUMCHECK", "GOINSECURE",
+	"GOTOOLCHAIN", "GOMODCACHE", "GOVCS",
+}
+
+// vendorEnv builds the environment for go mod vendor from base. Offline
+// modes pin GOTOOLCHAIN=local so a newer go directive fails instead of
+// triggering a toolchain download, and disable the checksum database:
+// go.sum is still verified, only the lookup of missing entries is off.
+func vendorEnv(base []string, opts VendorOptions) ([]string, error) {
+	mode := opts.Mode
+	if mode == "" {
+		mode = VendorOnline
+	}
+
+	inherited := make(map[string]string)
+	env := make([]string, 0, len(base)+8)
+	for _, kv := range base {
+		key, value, _ := strings.Cut(kv, "=")
+		if slices.Contains(controlledEnv, key) {
+			inherited[key] = value
+			continue
+		}
+		env = append(env, kv)
+	}
+
+	env = append(env, "GOFLAGS=-mod=mod")
+	if opts.GoWorkOff {
+		env = append(env, "GOWORK=off")
+	} else if v, ok := inherited["GOWORK"]; ok {
+		env = append(env, "GOWORK="+v)
+	}
+	if opts.ModCache != "" {
+		env = append(env, "GOMODCACHE="+opts.ModCache)
+	} else if v, ok := inherited["GOMODCACHE"]; ok {
+		env = append(env, "GOMODCACHE="+v)
+	}
+
+	switch mode {
+	case VendorOnline:
+		for _, key := range controlledEnv {
+			switch key {
+			case "GOFLAGS", "GOWORK", "GOMODCACHE":
+				continue
+			}
+			if v, ok := inherited[key]; ok {
+				env = append(env, key+"="+v)
+			}
+		}
+		return env, nil
+	case VendorModCache:
+		cache := opts.ModCache
+		if cache == "" {
+			cache = inherited["GOMODCACHE"]
+		}
+		if cache == "" {
+			cache = modcache.Dir()
+		}
+		if info, err := os.Stat(cache); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("module cache %s is not a directory", cache)
+		}
+		env = append(env, "GOPROXY=off")
+	case VendorOff:
+		env = append(env, "GOPROXY=off")
+	case VendorProxy:
+		if opts.ProxyDir == "" {
+			return nil, fmt.Errorf("vendor mode %s needs a proxy directory", mode)
+		}
+		proxyDir, err := filepath.Abs(opts.ProxyDir)
+		if err != nil {
+			return nil, fmt.Errorf("proxy directory: %w", err)
+		}
+		env = append(env, "GOPROXY=file://"+filepath.ToSlash(proxyDir))
+	default:
+		return nil, fmt.Errorf("unknown vendor mode %q", mode)
+	}
+
+	return append(env, "GOSUMDB=off", "GOTOOLCHAIN=local"), nil
+}
+
+type VendorResult struct {
+	HadVendor     bool         `json:"had_vendor"`
+	HasVendor     bool         `json:"has_vendor"`
+	VendorChanged bool         `json:"vendor_changed"`
+	GoModChanged  bool         `json:"go_mod_changed"`
+	GoSumChanged  bool         `json:"go_sum_changed"`
+	Report        VendorReport `json:"report"`
+	Prune         *PruneResult `json:"prune,omitempty"`
+}
 
+// Staged is the outcome of go mod vendor run against copies of go.mod and
+// go.sum in a staging directory.
+type Staged struct {
+	GoMod  string
+	GoSum  string
+	Vendor string
+	Prune  *PruneResult
+	// ModCache is the module cache tarball staged by StageModCache.
+	ModCache string
+}
+
+// StageVendor writes goMod and dir/go.sum into staging and runs go mod
+// vendor in dir with -modfile and -o pointing there, so relative
+// replacements resolve against dir while dir itself is left untouched.
+// Staged.Vendor does not exist when there is nothing to vendor.
+func StageVendor(ctx context.Context, dir, staging string, goMod []byte, opts VendorOptions) (Staged, error) {
+	staged, err := stageModFiles(dir, staging, goMod)
+	if err != nil {
+		return Staged{}, err
+	}
+	staged.Vendor = filepath.Join(staging, "vendor")
+
+	env, err := vendorEnv(os.Environ(), opts)
+	if err != nil {
+		return Staged{}, err
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "mod", "vendor", "-modfile="+staged.GoMod, "-o", staged.Vendor)
+	cmd.Dir = dir
+	cmd.Env = env
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stderr
 
-	var hadVendorBefore bool
-	vendorPath := filepath.Join(dir, "vendor")
-	info, err := os.Stat(vendorPath)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return Staged{}, fmt.Errorf("go mod vendor: interrupted")
+		}
+		return Staged{}, fmt.Errorf("go mod vendor: %w", err)
+	}
+
+	if opts.Prune != nil && isDir(staged.Vendor) {
+		result, err := Prune(staged.Vendor, *opts.Prune)
+		if err != nil {
+			return Staged{}, fmt.Errorf("prune vendor: %w", err)
+		}
+		staged.Prune = &result
+	}
+
+	return staged, nil
+}
+
+// stageModFiles copies goMod and dir/go.sum, when present, into staging.
+func stageModFiles(dir, staging string, goMod []byte) (Staged, error) {
+	staged := Staged{
+		GoMod: filepath.Join(staging, "go.mod"),
+		GoSum: filepath.Join(staging, "go.sum"),
+	}
 
-	if err == nil {
-		hadVendorBefore = info.IsDir()
-	} else if os.IsNotExist(err) {
-		hadVendorBefore = false
-	} else {
-		return false, fmt.Errorf("vendor check: %w", err)
+	if err := os.WriteFile(staged.GoMod, goMod, 0o644); err != nil {
+		return Staged{}, fmt.Errorf("stage go.mod: %w", err)
 	}
+	goSum, err := os.ReadFile(filepath.Join(dir, "go.sum"))
+	switch {
+	case err == nil:
+		if err := os.WriteFile(staged.GoSum, goSum, 0o644); err != nil {
+			return Staged{}, fmt.Errorf("stage go.sum: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return Staged{}, fmt.Errorf("read go.sum: %w", err)
+	}
+
+	return staged, nil
+}
+
+// Vendor runs go mod vendor in a staging directory inside dir and swaps
+// vendor/, go.mod and go.sum into place only after it succeeds. A failure
+// or interrupt at any point leaves dir as it was.
+func Vendor(dir string, opts VendorOptions) (VendorResult, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	err = cmd.Run()
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
 	if err != nil {
-		return false, fmt.Errorf("go mod vendor: %w", err)
+		return VendorResult{}, fmt.Errorf("read go.mod: %w", err)
 	}
 
-	info, err = os.Stat(vendorPath)
+	// Staging lives in dir so the swap is a rename on the same file system;
+	// the leading dot keeps it out of the go command's package patterns.
+	staging, err := os.MkdirTemp(dir, ".vlang-vendor-")
+	if err != nil {
+		return VendorResult{}, fmt.Errorf("create staging dir: %w", err)
+	}
+	defer os.RemoveAll(staging)
 
-	if err == nil && info.IsDir() {
-		if hadVendorBefore {
+	staged, err := StageVendor(ctx, dir, staging, goMod, opts)
+	if err != nil {
+		return VendorResult{}, err
+	}
+	if ctx.Err() != nil {
+		return VendorResult{}, fmt.Errorf("vendor: interrupted")
+	}
+
+	return applyStaged(dir, staging, staged)
+}
+
+func applyStaged(dir, staging string, staged Staged) (result VendorResult, err error) {
+	vendorDir := filepath.Join(dir, "vendor")
+	result.HadVendor = isDir(vendorDir)
+	result.HasVendor = isDir(staged.Vendor)
+	result.Prune = staged.Prune
+	result.Report = emptyVendorReport()
+
+	result.VendorChanged, err = treesDiffer(vendorDir, staged.Vendor)
+	if err != nil {
+		return VendorResult{}, err
+	}
+	if result.VendorChanged {
+		// Taken before the swap, while vendor/ still holds the old tree.
+		result.Report, err = DiffVendor(vendorDir, staged.Vendor)
+		if err != nil {
+			return VendorResult{}, err
+		}
+	}
+
+	var tx swapTx
+	defer func() {
+		if err != nil {
+			tx.rollback()
+		}
+	}()
+
+	if result.VendorChanged {
+		if err = tx.swap(vendorDir, staged.Vendor, filepath.Join(staging, "vendor.orig")); err != nil {
+			return VendorResult{}, err
+		}
+	}
+	if result.GoSumChanged, err = tx.swapFile(filepath.Join(dir, "go.sum"), staged.GoSum, filepath.Join(staging, "go.sum.orig")); err != nil {
+		return VendorResult{}, err
+	}
+	if result.GoModChanged, err = tx.swapFile(filepath.Join(dir, "go.mod"), staged.GoMod, filepath.Join(staging, "go.mod.orig")); err != nil {
+		return VendorResult{}, err
+	}
+
+	return result, nil
+}
+
+// swapTx replaces paths by renames and can undo them in reverse order.
+type swapTx struct {
+	undo []func() error
+}
+
+// swap moves target to backup and replacement to target. A missing
+// replacement just removes target; a missing target is just created.
+func (tx *swapTx) swap(target, replacement, backup string) error {
+	if exists(target) {
+		if err := os.Rename(target, backup); err != nil {
+			return fmt.Errorf("back up %s: %w", target, err)
+		}
+		tx.undo = append(tx.undo, func() error { return os.Rename(backup, target) })
+	}
+	if exists(replacement) {
+		if err := os.Rename(replacement, target); err != nil {
+			return fmt.Errorf("replace %s: %w", target, err)
+		}
+		tx.undo = append(tx.undo, func() error { return os.Rename(target, replacement) })
+	}
+	return nil
+}
+
+// swapFile swaps replacement in for target when their contents differ,
+// keeping the permissions of target.
+func (tx *swapTx) swapFile(target, replacement, backup string) (bool, error) {
+	changed, err := filesDiffer(target, replacement)
+	if err != nil || !changed {
+		return false, err
+	}
+	if exists(target) && exists(replacement) {
+		if err := keepMode(replacement, target); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.swap(target, replacement, backup)
+}
+
+func (tx *swapTx) rollback() {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		if err := tx.undo[i](); err != nil {
+			fmt.Fprintln(os.Stderr, "vendor rollback:", err)
+		}
+	}
+}
+
+func keepMode(path, like string) error {
+	info, err := os.Stat(like)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", like, err)
+	}
+	if err := os.Chmod(path, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("chmod %s: %w", path, err)
+	}
+	return nil
+}
+
+func filesDiffer(a, b string) (bool, error) {
+	dataA, existsA, err := readIfExists(a)
+	if err != nil {
+		return false, err
+	}
+	dataB, existsB, err := readIfExists(b)
+	if err != nil {
+		return false, err
+	}
+	return existsA != existsB || !bytes.Equal(dataA, dataB), nil
+}
+
+func treesDiffer(a, b string) (bool, error) {
+	filesA, err := treeFiles(a)
+	if err != nil {
+		return false, err
+	}
+	filesB, err := treeFiles(b)
+	if err != nil {
+		return false, err
+	}
+	if len(filesA) != len(filesB) {
+		return true, nil
+	}
+	for name, pathA := range filesA {
+		pathB, ok := filesB[name]
+		if !ok {
 			return true, nil
 		}
-		return false, nil
+		differ, err := filesDiffer(pathA, pathB)
+		if err != nil || differ {
+			return differ, err
+		}
 	}
+	return false, nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
 
-	return false, fmt.Errorf("vendorDir in %q not found", dir)
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
 }
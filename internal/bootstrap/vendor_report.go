@@ -0,0 +1,199 @@
+package bootstrap
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/reservation-v/vlang/internal/license"
+	"github.com/reservation-v/vlang/internal/modulestxt"
+	"github.com/reservation-v/vlang/internal/semver"
+)
+
+type ModuleChange struct {
+	Path string `json:"path"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+type LicenseChange struct {
+	Module string     `json:"module"`
+	File   string     `json:"file"`
+	Kind   ChangeKind `json:"kind"`
+}
+
+// VendorReport compares two vendor trees module by module. Versions are
+// the effective ones: the replacement version when a module is replaced
+// by another module version.
+type VendorReport struct {
+	Added      []ModuleChange  `json:"added"`
+	Removed    []ModuleChange  `json:"removed"`
+	Upgraded   []ModuleChange  `json:"upgraded"`
+	Downgraded []ModuleChange  `json:"downgraded"`
+	Licenses   []LicenseChange `json:"licenses"`
+}
+
+func (r VendorReport) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 &&
+		len(r.Upgraded) == 0 && len(r.Downgraded) == 0 && len(r.Licenses) == 0
+}
+
+// emptyVendorReport is the report of an unchanged vendor tree.
+func emptyVendorReport() VendorReport {
+	return VendorReport{
+		Added:      []ModuleChange{},
+		Removed:    []ModuleChange{},
+		Upgraded:   []ModuleChange{},
+		Downgraded: []ModuleChange{},
+		Licenses:   []LicenseChange{},
+	}
+}
+
+// DiffVendor reports how vendor tree newDir differs from oldDir. Either
+// may be missing.
+func DiffVendor(oldDir, newDir string) (VendorReport, error) {
+	report := emptyVendorReport()
+
+	oldMods, err := readVendored(oldDir)
+	if err != nil {
+		return VendorReport{}, err
+	}
+	newMods, err := readVendored(newDir)
+	if err != nil {
+		return VendorReport{}, err
+	}
+
+	for modPath, newVersion := range newMods {
+		oldVersion, ok := oldMods[modPath]
+		change := ModuleChange{Path: modPath, Old: oldVersion, New: newVersion}
+		switch {
+		case !ok:
+			change.Old = ""
+			report.Added = append(report.Added, change)
+		case oldVersion == newVersion:
+		case semver.Compare(newVersion, oldVersion) < 0:
+			report.Downgraded = append(report.Downgraded, change)
+		default:
+			report.Upgraded = append(report.Upgraded, change)
+		}
+	}
+	for modPath, oldVersion := range oldMods {
+		if _, ok := newMods[modPath]; !ok {
+			report.Removed = append(report.Removed, ModuleChange{Path: modPath, Old: oldVersion})
+		}
+	}
+	for _, list := range [][]ModuleChange{report.Added, report.Removed, report.Upgraded, report.Downgraded} {
+		sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+	}
+
+	report.Licenses, err = licenseChanges(oldDir, newDir, oldMods, newMods)
+	if err != nil {
+		return VendorReport{}, err
+	}
+
+	return report, nil
+}
+
+// readVendored maps module paths listed in dir/modules.txt to their
+// effective versions.
+func readVendored(dir string) (map[string]string, error) {
+	mods := make(map[string]string)
+	data, _, err := readIfExists(filepath.Join(dir, "modules.txt"))
+	if err != nil || len(data) == 0 {
+		return mods, err
+	}
+
+	file, err := modulestxt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Join(dir, "modules.txt"), err)
+	}
+	for _, mod := range file.Modules {
+		// Lines without a version only restate replace directives.
+		if mod.Version == "" {
+			continue
+		}
+		version := mod.Version
+		if mod.NewVersion != "" {
+			version = mod.NewVersion
+		}
+		mods[mod.Path] = version
+	}
+	return mods, nil
+}
+
+func licenseChanges(oldDir, newDir string, oldMods, newMods map[string]string) ([]LicenseChange, error) {
+	oldFiles, err := treeFiles(oldDir)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := treeFiles(newDir)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]string, 0, len(oldMods)+len(newMods))
+	for modPath := range oldMods {
+		modules = append(modules, modPath)
+	}
+	for modPath := range newMods {
+		if _, ok := oldMods[modPath]; !ok {
+			modules = append(modules, modPath)
+		}
+	}
+
+	names := make(map[string]bool)
+	for name := range oldFiles {
+		names[name] = true
+	}
+	for name := range newFiles {
+		names[name] = true
+	}
+
+	changes := []LicenseChange{}
+	for name := range names {
+		if !license.IsLicenseFile(path.Base(name)) {
+			continue
+		}
+		modPath := owningModule(modules, name)
+		if modPath == "" {
+			continue
+		}
+
+		oldPath, inOld := oldFiles[name]
+		newPath, inNew := newFiles[name]
+		var kind ChangeKind
+		switch {
+		case !inOld:
+			kind = ChangeCreated
+		case !inNew:
+			kind = ChangeDeleted
+		default:
+			differ, err := filesDiffer(oldPath, newPath)
+			if err != nil {
+				return nil, err
+			}
+			if !differ {
+				continue
+			}
+			kind = ChangeModified
+		}
+		changes = append(changes, LicenseChange{Module: modPath, File: name, Kind: kind})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].File < changes[j].File })
+
+	return changes, nil
+}
+
+// owningModule returns the longest module path that is a directory
+// prefix of the vendored file name.
+func owningModule(modules []string, name string) string {
+	best := ""
+	for _, modPath := range modules {
+		if strings.HasPrefix(name, modPath+"/") && len(modPath) > len(best) {
+			best = modPath
+		}
+	}
+	return best
+}
@@ -0,0 +1,64 @@
+package bootstrap
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiffVendor(t *testing.T) {
+	oldDir := t.TempDir()
+	newDir := t.TempDir()
+
+	writePlanFile(t, filepath.Join(oldDir, "modules.txt"), ""+
+		"# example.com/gone v1.0.0\nexample.com/gone\n"+
+		"# example.com/up v1.2.0\nexample.com/up\n"+
+		"# example.com/down v0.3.0\nexample.com/down\n"+
+		"# example.com/same v1.0.0\nexample.com/same\n"+
+		"# example.com/repl v1.0.0 => example.com/fork v1.1.0\nexample.com/repl\n")
+	writePlanFile(t, filepath.Join(oldDir, "example.com", "gone", "LICENSE"), "MIT\n")
+	writePlanFile(t, filepath.Join(oldDir, "example.com", "up", "LICENSE"), "MIT\n")
+	writePlanFile(t, filepath.Join(oldDir, "example.com", "same", "COPYING"), "GPL\n")
+
+	writePlanFile(t, filepath.Join(newDir, "modules.txt"), ""+
+		"# example.com/new v0.1.0\nexample.com/new\n"+
+		"# example.com/up v1.10.0\nexample.com/up\n"+
+		"# example.com/down v0.2.9\nexample.com/down\n"+
+		"# example.com/same v1.0.0\nexample.com/same\n"+
+		"# example.com/repl v1.0.0 => example.com/fork v1.2.0\nexample.com/repl\n"+
+		"# example.com/local => ../local\n")
+	writePlanFile(t, filepath.Join(newDir, "example.com", "new", "LICENSE.md"), "Apache\n")
+	writePlanFile(t, filepath.Join(newDir, "example.com", "up", "LICENSE"), "BSD\n")
+	writePlanFile(t, filepath.Join(newDir, "example.com", "same", "COPYING"), "GPL\n")
+	writePlanFile(t, filepath.Join(newDir, "example.com", "up", "license.go"), "package up\n")
+
+	got, err := DiffVendor(oldDir, newDir)
+	if err != nil {
+		t.Fatalf("DiffVendor() error: %v", err)
+	}
+
+	want := VendorReport{
+		Added:      []ModuleChange{{Path: "example.com/new", New: "v0.1.0"}},
+		Removed:    []ModuleChange{{Path: "example.com/gone", Old: "v1.0.0"}},
+		Upgraded:   []ModuleChange{{Path: "example.com/repl", Old: "v1.1.0", New: "v1.2.0"}, {Path: "example.com/up", Old: "v1.2.0", New: "v1.10.0"}},
+		Downgraded: []ModuleChange{{Path: "example.com/down", Old: "v0.3.0", New: "v0.2.9"}},
+		Licenses: []LicenseChange{
+			{Module: "example.com/gone", File: "example.com/gone/LICENSE", Kind: ChangeDeleted},
+			{Module: "example.com/new", File: "example.com/new/LICENSE.md", Kind: ChangeCreated},
+			{Module: "example.com/up", File: "example.com/up/LICENSE", Kind: ChangeModified},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffVendor():\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestDiffVendorMissingTrees(t *testing.T) {
+	got, err := DiffVendor(filepath.Join(t.TempDir(), "vendor"), filepath.Join(t.TempDir(), "vendor"))
+	if err != nil {
+		t.Fatalf("DiffVendor() error: %v", err)
+	}
+	if !got.Empty() {
+		t.Errorf("DiffVendor() of missing trees: got %+v, want empty", got)
+	}
+}
@@ -0,0 +1,251 @@
+package bootstrap
+
+import (
+	"archive/zip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/reservation-v/vlang/internal/dirhash"
+	"github.com/reservation-v/vlang/internal/modcache"
+)
+
+func TestVendorEnv(t *testing.T) {
+	base := []string{
+		"HOME=/home/builder",
+		"GOFLAGS=-mod=vendor -trimpath",
+		"GOPROXY=https://proxy.example.com",
+		"GOSUMDB=sum.golang.org",
+		"GONOSUMDB=example.com/private",
+		"GOTOOLCHAIN=auto",
+	}
+	cache := t.TempDir()
+
+	tests := []struct {
+		name    string
+		opts    VendorOptions
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "online_inherits_proxy",
+			opts: VendorOptions{},
+			want: []string{"HOME=/home/builder", "GOFLAGS=-mod=mod", "GOPROXY=https://proxy.example.com",
+				"GOSUMDB=sum.golang.org", "GONOSUMDB=example.com/private", "GOTOOLCHAIN=auto"},
+		},
+		{
+			name: "modcache",
+			opts: VendorOptions{Mode: VendorModCache, ModCache: cache, GoWorkOff: true},
+			want: []string{"HOME=/home/builder", "GOFLAGS=-mod=mod", "GOWORK=off", "GOMODCACHE=" + cache,
+				"GOPROXY=off", "GOSUMDB=off", "GOTOOLCHAIN=local"},
+		},
+		{
+			name: "proxy",
+			opts: VendorOptions{Mode: VendorProxy, ProxyDir: "/srv/goproxy"},
+			want: []string{"HOME=/home/builder", "GOFLAGS=-mod=mod", "GOPROXY=file:///srv/goproxy",
+				"GOSUMDB=off", "GOTOOLCHAIN=local"},
+		},
+		{
+			name: "off",
+			opts: VendorOptions{Mode: VendorOff},
+			want: []string{"HOME=/home/builder", "GOFLAGS=-mod=mod", "GOPROXY=off", "GOSUMDB=off", "GOTOOLCHAIN=local"},
+		},
+		{name: "proxy_without_dir", opts: VendorOptions{Mode: VendorProxy}, wantErr: true},
+		{name: "missing_modcache", opts: VendorOptions{Mode: VendorModCache, ModCache: filepath.Join(cache, "nope")}, wantErr: true},
+		{name: "unknown_mode", opts: VendorOptions{Mode: "ftp"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := vendorEnv(base, tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("vendorEnv() want error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("vendorEnv() error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("vendorEnv():\ngot  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVendorFromFileProxy(t *testing.T) {
+	proxy := t.TempDir()
+	writeProxyModule(t, proxy, "example.com/dep", "v1.0.0", map[string]string{
+		"go.mod": "module example.com/dep\n\ngo 1.21\n",
+		"dep.go": "package dep\n\nconst X = 1\n",
+	})
+
+	dir := t.TempDir()
+	writePlanFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.21\n\nrequire example.com/dep v1.0.0\n")
+	writePlanFile(t, filepath.Join(dir, "main.go"), "package main\n\nimport _ \"example.com/dep\"\n\nfunc main() {}\n")
+	writePlanFile(t, filepath.Join(dir, "go.sum"), proxyGoSum(t, proxy, "example.com/dep", "v1.0.0"))
+
+	cache := t.TempDir()
+	t.Cleanup(func() { makeWritable(cache) })
+
+	stale := filepath.Join(dir, "vendor", "modules.txt")
+	writePlanFile(t, stale, "# example.com/dep v0.9.0\nexample.com/dep\n")
+
+	offline := VendorOptions{Mode: VendorModCache, ModCache: cache}
+	if _, err := Vendor(dir, offline); err == nil {
+		t.Fatalf("Vendor() from an empty module cache: want error, got nil")
+	}
+	if data, _ := os.ReadFile(stale); string(data) != "# example.com/dep v0.9.0\nexample.com/dep\n" {
+		t.Fatalf("failed Vendor() touched vendor/: %q", data)
+	}
+	assertNoStaging(t, dir)
+
+	result, err := Vendor(dir, VendorOptions{Mode: VendorProxy, ProxyDir: proxy, ModCache: cache})
+	if err != nil {
+		t.Fatalf("Vendor() from file proxy: %v", err)
+	}
+	want := VendorResult{HadVendor: true, HasVendor: true, VendorChanged: true, Report: VendorReport{
+		Added:      []ModuleChange{},
+		Removed:    []ModuleChange{},
+		Upgraded:   []ModuleChange{{Path: "example.com/dep", Old: "v0.9.0", New: "v1.0.0"}},
+		Downgraded: []ModuleChange{},
+		Licenses:   []LicenseChange{},
+	}}
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("Vendor() result: got %+v, want %+v", result, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "vendor", "example.com", "dep", "dep.go")); err != nil {
+		t.Fatalf("vendored file missing: %v", err)
+	}
+	assertNoStaging(t, dir)
+
+	// The proxy filled the cache, so the offline mode now succeeds too.
+	result, err = Vendor(dir, offline)
+	if err != nil {
+		t.Fatalf("Vendor() from filled module cache: %v", err)
+	}
+	if want := (VendorResult{HadVendor: true, HasVendor: true, Report: emptyVendorReport()}); !reflect.DeepEqual(result, want) {
+		t.Fatalf("repeated Vendor() result: got %+v, want %+v", result, want)
+	}
+}
+
+func TestApplyStagedRollback(t *testing.T) {
+	dir := t.TempDir()
+	staging := t.TempDir()
+	writePlanFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n")
+	writePlanFile(t, filepath.Join(dir, "vendor", "modules.txt"), "# old\n")
+
+	staged := Staged{
+		GoMod:  filepath.Join(staging, "go.mod"),
+		GoSum:  filepath.Join(staging, "go.sum"),
+		Vendor: filepath.Join(staging, "vendor"),
+	}
+	writePlanFile(t, staged.GoMod, "module example.com/app\n\ngo 1.22\n")
+	writePlanFile(t, filepath.Join(staged.Vendor, "modules.txt"), "# new\n")
+	// go.sum cannot be swapped in: a directory sits where its backup goes.
+	writePlanFile(t, staged.GoSum, "sum\n")
+	writePlanFile(t, filepath.Join(dir, "go.sum"), "old sum\n")
+	writePlanFile(t, filepath.Join(staging, "go.sum.orig", "blocker"), "")
+
+	if _, err := applyStaged(dir, staging, staged); err == nil {
+		t.Fatalf("applyStaged() want error, got nil")
+	}
+	for path, want := range map[string]string{
+		"go.mod":             "module example.com/app\n",
+		"go.sum":             "old sum\n",
+		"vendor/modules.txt": "# old\n",
+	} {
+		if data, _ := os.ReadFile(filepath.Join(dir, path)); string(data) != want {
+			t.Errorf("%s after rollback: got %q, want %q", path, data, want)
+		}
+	}
+}
+
+func assertNoStaging(t *testing.T, dir string) {
+	t.Helper()
+	matches, _ := filepath.Glob(filepath.Join(dir, ".vlang-vendor-*"))
+	if len(matches) > 0 {
+		t.Fatalf("staging left behind: %v", matches)
+	}
+}
+
+func writeProxyModule(t *testing.T, proxy, path, version string, files map[string]string) {
+	t.Helper()
+	dir := filepath.Join(proxy, filepath.FromSlash(modcache.Escape(path)), "@v")
+	writePlanFile(t, filepath.Join(dir, "list"), version+"\n")
+	writePlanFile(t, filepath.Join(dir, version+".info"), `{"Version":"`+version+`","Time":"2024-01-01T00:00:00Z"}`)
+	writePlanFile(t, filepath.Join(dir, version+".mod"), files["go.mod"])
+
+	zipFile, err := os.Create(filepath.Join(dir, version+".zip"))
+	if err != nil {
+		t.Fatalf("create zip: %v", err)
+	}
+	zw := zip.NewWriter(zipFile)
+	for name, content := range files {
+		w, err := zw.Create(path + "@" + version + "/" + name)
+		if err != nil {
+			t.Fatalf("zip %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("zip %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	if err := zipFile.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+}
+
+func proxyGoSum(t *testing.T, proxy, path, version string) string {
+	t.Helper()
+	dir := filepath.Join(proxy, filepath.FromSlash(modcache.Escape(path)), "@v")
+
+	extracted := t.TempDir()
+	zr, err := zip.OpenReader(filepath.Join(dir, version+".zip"))
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	defer zr.Close()
+	prefix := path + "@" + version + "/"
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+		writePlanFile(t, filepath.Join(extracted, strings.TrimPrefix(f.Name, prefix)), string(data))
+	}
+
+	zipHash, err := dirhash.HashDir(extracted, path+"@"+version)
+	if err != nil {
+		t.Fatalf("hash module: %v", err)
+	}
+	mod, _ := os.ReadFile(filepath.Join(dir, version+".mod"))
+	modHash, err := dirhash.HashGoMod(mod)
+	if err != nil {
+		t.Fatalf("hash go.mod: %v", err)
+	}
+
+	return path + " " + version + " " + zipHash + "\n" + path + " " + version + "/go.mod " + modHash + "\n"
+}
+
+func makeWritable(root string) {
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			os.Chmod(path, 0o755)
+		}
+		return nil
+	})
+}
@@ -4,14 +4,37 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/reservation-v/vlang/internal/bootstrap"
+	"github.com/reservation-v/vlang/internal/config"
+	"github.com/reservation-v/vlang/internal/gear"
+	"github.com/reservation-v/vlang/internal/inspect"
+	"github.com/reservation-v/vlang/internal/modfile"
+	"github.com/reservation-v/vlang/internal/rpmver"
+	"github.com/reservation-v/vlang/internal/validate"
 )
 
 type bootstrapFlags struct {
-	Dir    string
-	Vendor bool
-	Out    OutputFlags
+	Dir           string
+	Module        string
+	Vendor        bool
+	Deps          string
+	GoWork        string
+	VendorOpts    bootstrap.VendorOptions
+	Edits         bootstrap.ModEdits
+	Rules         bool
+	Gear          gear.RulesOptions
+	Spec          bool
+	OverwriteSpec bool
+	Version       string
+	SpecOpts      gear.SpecOptions
+	Config        string
+	TemplateDir   string
+	DryRun        bool
+	Prune         *bool // nil unless -prune was given
+	Out           OutputFlags
 }
 
 func RunBootstrap(args []string) error {
@@ -24,18 +47,67 @@ func RunBootstrap(args []string) error {
 	if err != nil {
 		return err
 	}
-	bootstrapFlgs.Dir = absDir
+	bootstrapFlgs.Dir, err = moduleDir(absDir, bootstrapFlgs.Module)
+	if err != nil {
+		return err
+	}
+
+	vendorOpts, err := vendorOptions(bootstrapFlgs)
+	if err != nil {
+		return err
+	}
+
+	if bootstrapFlgs.DryRun {
+		return runBootstrapDryRun(bootstrapFlgs, vendorOpts)
+	}
+
+	goModEdited, err := bootstrap.EditGoMod(bootstrapFlgs.Dir, bootstrapFlgs.Edits)
+	if err != nil {
+		return fmt.Errorf("edit go.mod: %w", err)
+	}
+
+	var vendorInfo VendorInfo
+	var modCache *bootstrap.ModCacheResult
+	if bootstrapFlgs.Deps == "modcache" {
+		vendorInfo = VendorInfo{Enabled: false, Status: "skipped"}
+		modCache, err = getModCacheInfo(bootstrapFlgs.Vendor, bootstrapFlgs.Dir, vendorOpts)
+		if err != nil {
+			return fmt.Errorf("modcache: %w", err)
+		}
+	} else {
+		vendorInfo, err = getVendorInfo(bootstrapFlgs.Vendor, bootstrapFlgs.Dir, vendorOpts)
+		if err != nil {
+			return fmt.Errorf("get_vendor info: %w", err)
+		}
+	}
 
-	vendorInfo, err := getVendorInfo(bootstrapFlgs.Vendor, bootstrapFlgs.Dir)
+	// Inspect once, after go.mod and the dependencies took their final form.
+	facts, err := inspect.Inspect(bootstrapFlgs.Dir)
 	if err != nil {
-		return fmt.Errorf("get_vendor info: %w", err)
+		return fmt.Errorf("inspect: %w", err)
 	}
+	projectInfo := bootstrap.NewProjectInfo(facts)
 
-	projectInfo, err := bootstrap.Inspect(bootstrapFlgs.Dir)
+	tmpl, err := gearTemplates(bootstrapFlgs)
+	if err != nil {
+		return err
+	}
+
+	gearData, err := getGearData(bootstrapFlgs, facts)
 	if err != nil {
 		return fmt.Errorf("inspect: %w", err)
 	}
 
+	rulesInfo, err := writeGearFile(planRules(bootstrapFlgs, tmpl, gearData))
+	if err != nil {
+		return fmt.Errorf("gear rules: %w", err)
+	}
+
+	specInfo, err := writeGearFile(planSpec(bootstrapFlgs, tmpl, gearData))
+	if err != nil {
+		return fmt.Errorf("gear spec: %w", err)
+	}
+
 	writer, closeFn, existed, err := openOutputWriter(bootstrapFlgs.Out.Output)
 
 	if err != nil {
@@ -49,7 +121,14 @@ func RunBootstrap(args []string) error {
 		}
 	}()
 
-	err = WriteOutput(writer, bootstrapFlgs.Out.Format, projectInfo, vendorInfo)
+	err = WriteOutput(writer, bootstrapFlgs.Out.Format, BootstrapOutput{
+		ProjectInfo: projectInfo,
+		Vendor:      vendorInfo,
+		ModCache:    modCache,
+		GoModEdited: goModEdited,
+		Rules:       rulesInfo,
+		Spec:        specInfo,
+	})
 	if err != nil {
 		return fmt.Errorf("write output: %w", err)
 	}
@@ -70,39 +149,293 @@ func parseBootstrapFlags(args []string) (bootstrapFlags, error) {
 	fs.SetOutput(os.Stderr)
 
 	dirPtr := addDirFlag(fs)
+	modulePtr := addModuleFlag(fs)
 	needVendor := fs.Bool("vendor", true, "enable/disable vendoring (true/false)")
+	deps := fs.String("deps", "vendor", "how dependencies are shipped: vendor (vendor/ tree) or modcache (module cache tarball)")
+	goWork := fs.String("gowork", "refuse", "go.work handling when dir is in a workspace (refuse, off)")
+	var vendorOpts bootstrap.VendorOptions
+	fs.StringVar((*string)(&vendorOpts.Mode), "vendor-mode", string(bootstrap.VendorOnline),
+		"where go mod vendor gets modules: online (ambient GOPROXY), modcache, proxy (file:// dir), off")
+	fs.StringVar(&vendorOpts.ModCache, "modcache", "", "module cache to vendor from (sets GOMODCACHE)")
+	fs.StringVar(&vendorOpts.ProxyDir, "proxy-dir", "", "GOPROXY directory for -vendor-mode=proxy")
+	needRules := fs.Bool("rules", true, "generate .gear/rules when missing (true/false)")
+	tagPrefix := fs.String("tag-prefix", "v", "prefix of upstream version tags")
+	vendorTarball := fs.Bool("vendor-tarball", false, "pack vendor/ as a separate source tarball")
+	needSpec := fs.Bool("spec", true, "generate .gear/<name>.spec when missing (true/false)")
+	overwriteSpec := fs.Bool("overwrite-spec", false, "replace an existing .gear/<name>.spec")
+	version := fs.String("version", "", "upstream Go version (vX.Y.Z) to put into the spec (default: the version of the existing spec, else the release tag at HEAD)")
+	var specOpts gear.SpecOptions
+	fs.StringVar(&specOpts.Summary, "summary", "", "spec Summary")
+	fs.Var((*stringList)(&specOpts.Licenses), "license", "spec License identifier (repeatable)")
+	fs.StringVar(&specOpts.Group, "group", "", "spec Group")
+	fs.StringVar(&specOpts.URL, "url", "", "spec Url (default https://<import path>)")
+	fs.StringVar(&specOpts.Packager, "packager", os.Getenv("PACKAGER"), "changelog author, Name <email>")
+	configPath := fs.String("config", "", "config file (default <dir>/.gear/vlang.json when present)")
+	templateDir := fs.String("template-dir", "", "directory with <name>.tmpl overrides for generated files")
+	dryRun := fs.Bool("dry-run", false, "print the planned changes as a diff (text) or change list (json) without touching dir")
+	prune := fs.Bool("prune", false, "prune vendor/ of files not needed to build (default from config prune.enabled)")
 	format, output := addOutputFlags(fs)
+
+	var edits bootstrap.ModEdits
+	fs.StringVar(&edits.GoVersion, "go", "", "set the go directive in go.mod before vendoring")
+	fs.StringVar(&edits.Toolchain, "toolchain", "", "set the toolchain directive in go.mod (none to drop it)")
+	fs.Var((*stringList)(&edits.Replace), "replace", "add replace old[@version]=new[@version] to go.mod (repeatable)")
+	fs.Var((*stringList)(&edits.DropReplace), "drop-replace", "drop replace old[@version] from go.mod (repeatable)")
+	fs.Var((*stringList)(&edits.Exclude), "exclude", "add exclude path@version to go.mod (repeatable)")
+	fs.Var((*stringList)(&edits.DropExclude), "drop-exclude", "drop exclude path@version from go.mod (repeatable)")
+
 	if err := fs.Parse(args); err != nil {
 		return bootstrapFlags{}, err
 	}
 
+	var prunePtr *bool
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "prune" {
+			prunePtr = prune
+		}
+	})
+
 	bsFlags := bootstrapFlags{
-		Dir:    *dirPtr,
-		Vendor: *needVendor,
-		Out:    OutputFlags{Format: *format, Output: *output},
+		Dir:           *dirPtr,
+		Module:        *modulePtr,
+		Vendor:        *needVendor,
+		Deps:          *deps,
+		GoWork:        *goWork,
+		VendorOpts:    vendorOpts,
+		Edits:         edits,
+		Rules:         *needRules,
+		Gear:          gear.RulesOptions{TagPrefix: *tagPrefix, VendorTarball: *vendorTarball, ModCache: *deps == "modcache"},
+		Spec:          *needSpec,
+		OverwriteSpec: *overwriteSpec,
+		Version:       *version,
+		SpecOpts:      specOpts,
+		Config:        *configPath,
+		TemplateDir:   *templateDir,
+		DryRun:        *dryRun,
+		Prune:         prunePtr,
+		Out:           OutputFlags{Format: *format, Output: *output},
 	}
 
 	return bsFlags, nil
 }
 
-func getVendorInfo(needVendor bool, dir string) (VendorInfo, error) {
-	vendorInfo := VendorInfo{}
-	if !needVendor {
-		vendorInfo.Status = "skipped"
-		vendorInfo.Enabled = false
-	} else {
-		hadVendorBefore, err := bootstrap.Vendor(dir)
-		if err != nil {
-			return VendorInfo{}, fmt.Errorf("vendor: %w", err)
+func vendorOptions(flags bootstrapFlags) (bootstrap.VendorOptions, error) {
+	opts := flags.VendorOpts
+
+	cfg, err := config.Load(flags.Dir, flags.Config)
+	if err != nil {
+		return bootstrap.VendorOptions{}, err
+	}
+	if pruneEnabled(flags.Prune, cfg.Prune) {
+		opts.Prune = &bootstrap.PruneOptions{}
+		if cfg.Prune != nil {
+			opts.Prune.Allow = cfg.Prune.Allow
+			opts.Prune.Deny = cfg.Prune.Deny
 		}
-		if hadVendorBefore {
-			vendorInfo.Status = "updated"
-			vendorInfo.Enabled = true
-		} else {
-			vendorInfo.Status = "created"
-			vendorInfo.Enabled = true
+	}
+
+	switch flags.Deps {
+	case "vendor":
+	case "modcache":
+		opts.Prune = nil
+	default:
+		return bootstrap.VendorOptions{}, fmt.Errorf("unknown -deps %q", flags.Deps)
+	}
+
+	switch opts.Mode {
+	case bootstrap.VendorOnline, bootstrap.VendorModCache, bootstrap.VendorOff:
+	case bootstrap.VendorProxy:
+		if opts.ProxyDir == "" {
+			return bootstrap.VendorOptions{}, fmt.Errorf("-vendor-mode=proxy needs -proxy-dir")
 		}
+	default:
+		return bootstrap.VendorOptions{}, fmt.Errorf("unknown -vendor-mode %q", opts.Mode)
+	}
+
+	switch flags.GoWork {
+	case "off":
+		opts.GoWorkOff = true
+		return opts, nil
+	case "refuse":
+		if !flags.Vendor {
+			return opts, nil
+		}
+		if issue := validate.CheckWorkspace(flags.Dir); issue != nil {
+			return bootstrap.VendorOptions{}, fmt.Errorf("%s: %s (%s); rerun with -gowork=off to vendor outside the workspace",
+				issue.Code, issue.Message, issue.Path)
+		}
+		return opts, nil
+	default:
+		return bootstrap.VendorOptions{}, fmt.Errorf("unknown -gowork mode %q", flags.GoWork)
+	}
+}
+
+func pruneEnabled(flag *bool, cfg *config.Prune) bool {
+	if flag != nil {
+		return *flag
+	}
+	return cfg != nil && cfg.Enabled
+}
+
+func getVendorInfo(needVendor bool, dir string, opts bootstrap.VendorOptions) (VendorInfo, error) {
+	if !needVendor {
+		return VendorInfo{Enabled: false, Status: "skipped"}, nil
+	}
+
+	result, err := bootstrap.Vendor(dir, opts)
+	if err != nil {
+		return VendorInfo{}, fmt.Errorf("vendor: %w", err)
+	}
+
+	vendorInfo := VendorInfo{Enabled: true, Result: &result}
+	switch {
+	case !result.HasVendor:
+		vendorInfo.Status = "none"
+	case !result.HadVendor:
+		vendorInfo.Status = "created"
+	case result.VendorChanged:
+		vendorInfo.Status = "updated"
+	default:
+		vendorInfo.Status = "unchanged"
 	}
 
 	return vendorInfo, nil
 }
+
+func getModCacheInfo(needDeps bool, dir string, opts bootstrap.VendorOptions) (*bootstrap.ModCacheResult, error) {
+	if !needDeps {
+		return nil, nil
+	}
+
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("read go.mod: %w", err)
+	}
+	name, err := moduleName(goMod)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := bootstrap.ModCache(dir, bootstrap.ModCachePath(dir, name), opts)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// moduleName is the package name of the module goMod declares, all the
+// module cache tarball needs to know before the tree is inspected.
+func moduleName(goMod []byte) (string, error) {
+	modulePath, err := modfile.ParseModulePath(goMod)
+	if err != nil {
+		return "", fmt.Errorf("parse module path: %w", err)
+	}
+	return inspect.NameFromModulePath(modulePath)
+}
+
+func gearTemplates(flags bootstrapFlags) (gear.Templates, error) {
+	if flags.TemplateDir != "" {
+		return gear.Templates{Dir: flags.TemplateDir}, nil
+	}
+
+	cfg, err := config.Load(flags.Dir, flags.Config)
+	if err != nil {
+		return gear.Templates{}, err
+	}
+
+	return gear.Templates{Dir: cfg.TemplateDir}, nil
+}
+
+func getGearData(flags bootstrapFlags, facts inspect.Info) (gear.Data, error) {
+	if err := inspect.AddVendorCgo(&facts); err != nil {
+		return gear.Data{}, err
+	}
+
+	return gear.Data{Project: facts, Rules: flags.Gear, Spec: flags.SpecOpts}, nil
+}
+
+func planRules(flags bootstrapFlags, tmpl gear.Templates, data gear.Data) (gear.Output, error) {
+	if !flags.Rules {
+		return gear.Output{Status: gear.FileSkipped}, nil
+	}
+	out, err := gear.PlanRules(flags.Dir, tmpl, data)
+	if err == nil && out.Unmanaged {
+		fmt.Fprintf(os.Stderr, "%s was kept: it was not generated by vlang; remove it to regenerate it and merge local edits on later runs\n", out.Path)
+	}
+	return out, err
+}
+
+func planSpec(flags bootstrapFlags, tmpl gear.Templates, data gear.Data) (gear.Output, error) {
+	if !flags.Spec {
+		return gear.Output{Status: gear.FileSkipped}, nil
+	}
+
+	ok, err := specVersion(flags, &data)
+	if err != nil {
+		return gear.Output{}, err
+	}
+	if !ok {
+		fmt.Fprintln(os.Stderr, "spec was skipped: -version is not set")
+		return gear.Output{Status: gear.FileSkipped}, nil
+	}
+
+	docs, err := gear.DocFiles(flags.Dir)
+	if err != nil {
+		return gear.Output{}, err
+	}
+	data.Spec.Docs = docs
+	if len(data.Spec.Licenses) == 0 && len(data.Project.Licenses.Unknown) > 0 {
+		fmt.Fprintf(os.Stderr, "spec License: is Unknown: no license or no -only/-or-later form found for %s; set it with -license\n",
+			strings.Join(data.Project.Licenses.Unknown, ", "))
+	}
+
+	out, err := gear.PlanSpec(flags.Dir, tmpl, data, flags.OverwriteSpec)
+	if err == nil && out.Unmanaged {
+		fmt.Fprintf(os.Stderr, "%s was kept: it has no recorded generation in %s; rerun with -overwrite-spec once to regenerate it (%%changelog is kept) and merge local edits on later runs\n",
+			out.Path, filepath.Dir(gear.BasePath(out.Path)))
+	}
+	return out, err
+}
+
+// specVersion fills the spec Version and Release from -version, else
+// from the spec bootstrap generated before, else from the release tag at
+// HEAD. ok is false when none of them is known.
+func specVersion(flags bootstrapFlags, data *gear.Data) (bool, error) {
+	version := flags.Version
+	if version == "" {
+		recorded, release, ok, err := gear.RecordedVersion(gear.SpecPath(flags.Dir, data.Project.Name))
+		if err != nil || ok {
+			data.Spec.Version, data.Spec.Release = recorded, release
+			return ok, err
+		}
+		// Only a tag at HEAD: the rules pack the tree of the version tag.
+		if g := data.Project.Git; g != nil && g.Tag != "" && g.CommitsSinceTag == 0 {
+			version = g.Tag
+		}
+	}
+	if version == "" {
+		return false, nil
+	}
+
+	mapped, err := rpmver.FromGo(version)
+	if err != nil {
+		return false, fmt.Errorf("map version: %w", err)
+	}
+	data.Spec.Upstream = version
+	data.Spec.Version = mapped.Version
+	data.Spec.Release = mapped.Release
+	return true, nil
+}
+
+func writeGearFile(out gear.Output, err error) (GearFileInfo, error) {
+	if err != nil {
+		return GearFileInfo{}, err
+	}
+	if err := out.Write(); err != nil {
+		return GearFileInfo{}, err
+	}
+	if out.Status == gear.FileConflict {
+		fmt.Fprintf(os.Stderr, "%s has merge conflicts, resolve the marked regions\n", out.Path)
+	}
+	return GearFileInfo{Path: out.Path, Status: out.Status}, nil
+}
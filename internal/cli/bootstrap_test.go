@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runBootstrap(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "bootstrap.json")
+	args = append([]string{"-dir", dir, "-vendor=false", "-packager", "Test <test@example.com>",
+		"-format", "json", "-output", out}, args...)
+	if err := RunBootstrap(args); err != nil {
+		t.Fatalf("RunBootstrap(%v) error: %v", args, err)
+	}
+}
+
+func readSpec(t *testing.T, dir string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, ".gear", "tool.spec"))
+	if err != nil {
+		t.Fatalf("read spec: %v", err)
+	}
+	return string(data)
+}
+
+func TestBootstrapSpecVersionFallback(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/tool\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {}\n")
+
+	// Outside git and without a spec there is no version to use.
+	runBootstrap(t, dir)
+	if _, err := os.Stat(filepath.Join(dir, ".gear", "tool.spec")); !os.IsNotExist(err) {
+		t.Fatalf("spec written without a version: %v", err)
+	}
+
+	runBootstrap(t, dir, "-version", "v1.0.0")
+	spec := readSpec(t, dir)
+	if !strings.Contains(spec, "Version: 1.0.0\n") || !strings.Contains(spec, "%_bindir/tool\n") {
+		t.Fatalf("first spec:\n%s", spec)
+	}
+
+	// A hand edit and a new binary, regenerated without -version.
+	spec = strings.Replace(spec, "%description\n", "%description\nHand-written description.\n", 1)
+	writeFile(t, filepath.Join(dir, ".gear", "tool.spec"), spec)
+	writeFile(t, filepath.Join(dir, "cmd", "helper", "main.go"), "package main\n\nfunc main() {}\n")
+	runBootstrap(t, dir)
+
+	spec = readSpec(t, dir)
+	for _, want := range []string{"Version: 1.0.0\n", "Hand-written description.\n", "%_bindir/helper\n", "%_bindir/tool\n"} {
+		if !strings.Contains(spec, want) {
+			t.Errorf("merged spec missing %q:\n%s", want, spec)
+		}
+	}
+	if n := strings.Count(spec, "\n* "); n != 1 {
+		t.Errorf("merged spec has %d changelog entries, want 1:\n%s", n, spec)
+	}
+
+	runBootstrap(t, dir, "-version", "v1.1.0")
+	spec = readSpec(t, dir)
+	_, changelog, _ := strings.Cut(spec, "%changelog\n")
+	if !strings.Contains(spec, "Version: 1.1.0\n") || !strings.Contains(spec, "Hand-written description.\n") ||
+		!strings.Contains(changelog, "1.1.0-alt1\n- New version 1.1.0.\n\n* ") ||
+		!strings.HasSuffix(changelog, "1.0.0-alt1\n- Initial build for ALT Sisyphus.\n") {
+		t.Fatalf("spec after version bump:\n%s", spec)
+	}
+}
+
+func TestBootstrapSpecLicenseUnknown(t *testing.T) {
+	dir := writeProject(t)
+	gpl, err := os.ReadFile(filepath.Join("..", "license", "texts", "GPL-3.0.txt"))
+	if err != nil {
+		t.Fatalf("read GPL-3.0 text: %v", err)
+	}
+	// The stock COPYING does not say whether later versions apply.
+	writeFile(t, filepath.Join(dir, "COPYING"), string(gpl))
+
+	runBootstrap(t, dir, "-vendor=true", "-version", "v1.0.0")
+	spec := readSpec(t, dir)
+	for _, want := range []string{"License: Unknown\n", "%license COPYING vendor/example.com/lib/LICENSE\n"} {
+		if !strings.Contains(spec, want) {
+			t.Errorf("spec missing %q:\n%s", want, spec)
+		}
+	}
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL="+os.DevNull, "GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+func TestBootstrapSpecVersionFromTag(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/tool\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nfunc main() {}\n")
+	git(t, dir, "init", "-q", "-b", "main")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "initial")
+	git(t, dir, "tag", "v0.3.0")
+	writeFile(t, filepath.Join(dir, "extra.go"), "package main\n")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "second")
+
+	// HEAD is past the tag: its pseudo-version has no tag for the rules.
+	runBootstrap(t, dir, "-rules=false")
+	if _, err := os.Stat(filepath.Join(dir, ".gear", "tool.spec")); !os.IsNotExist(err) {
+		t.Fatalf("spec written for an untagged HEAD: %v", err)
+	}
+
+	git(t, dir, "tag", "v0.4.0")
+	runBootstrap(t, dir, "-rules=false")
+	if spec := readSpec(t, dir); !strings.Contains(spec, "Version: 0.4.0\nRelease: alt1\n") {
+		t.Fatalf("spec for tagged HEAD:\n%s", spec)
+	}
+}
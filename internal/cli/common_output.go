@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+
+	"github.com/reservation-v/vlang/internal/inspect"
 )
 
 type OutputFlags struct {
@@ -60,6 +62,21 @@ func addDirFlag(fs *flag.FlagSet) *string {
 	return dirPtr
 }
 
+func addModuleFlag(fs *flag.FlagSet) *string {
+	return fs.String("module", "", "module to target in a multi-module repo (relative dir or module path)")
+}
+
+func moduleDir(dir, selector string) (string, error) {
+	if selector == "" {
+		return dir, nil
+	}
+	selected, err := inspect.SelectModule(dir, selector)
+	if err != nil {
+		return "", fmt.Errorf("select module: %w", err)
+	}
+	return selected, nil
+}
+
 func absPath(path string) (string, error) {
 	absDir, err := filepath.Abs(path)
 	if err != nil {
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/reservation-v/vlang/internal/bootstrap"
+	"github.com/reservation-v/vlang/internal/gear"
+	"github.com/reservation-v/vlang/internal/inspect"
+)
+
+// runBootstrapDryRun performs every bootstrap step against a staging
+// directory and reports the difference to flags.Dir.
+func runBootstrapDryRun(flags bootstrapFlags, vendorOpts bootstrap.VendorOptions) error {
+	staging, err := os.MkdirTemp("", "vlang-plan-")
+	if err != nil {
+		return fmt.Errorf("create staging dir: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	plan := bootstrap.NewPlan(flags.Dir)
+
+	_, goMod, err := bootstrap.PlanGoMod(flags.Dir, flags.Edits)
+	if err != nil {
+		return fmt.Errorf("edit go.mod: %w", err)
+	}
+
+	var vendorDir string
+	switch {
+	case flags.Vendor && flags.Deps == "modcache":
+		goMod, err = planModCache(plan, flags.Dir, staging, goMod, vendorOpts)
+	case flags.Vendor:
+		goMod, vendorDir, err = planVendor(plan, flags.Dir, staging, goMod, vendorOpts)
+	}
+	if err != nil {
+		return err
+	}
+	if err := plan.File("go.mod", goMod); err != nil {
+		return err
+	}
+
+	tmpl, err := gearTemplates(flags)
+	if err != nil {
+		return err
+	}
+	// The gear files describe the tree as bootstrap would leave it.
+	facts, err := inspect.InspectStaged(flags.Dir, inspect.Staged{GoMod: goMod, Vendor: vendorDir})
+	if err != nil {
+		return fmt.Errorf("inspect: %w", err)
+	}
+	gearData, err := getGearData(flags, facts)
+	if err != nil {
+		return fmt.Errorf("inspect: %w", err)
+	}
+	for _, planFn := range []func(bootstrapFlags, gear.Templates, gear.Data) (gear.Output, error){planRules, planSpec} {
+		out, err := planFn(flags, tmpl, gearData)
+		if err != nil {
+			return err
+		}
+		if err := planGearFile(plan, out); err != nil {
+			return err
+		}
+	}
+
+	plan.Sort()
+
+	return writeOutputWriter(flags.Out.Output, func(w io.Writer) error {
+		return WriteOutputPlan(w, flags.Out.Format, *plan)
+	})
+}
+
+// planVendor also returns the staged vendor directory.
+func planVendor(plan *bootstrap.Plan, dir, staging string, goMod []byte, opts bootstrap.VendorOptions) ([]byte, string, error) {
+	staged, err := bootstrap.StageVendor(context.Background(), dir, staging, goMod, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := plan.Tree("vendor", staged.Vendor); err != nil {
+		return nil, "", err
+	}
+	if err := plan.VendorModules(staged.Vendor); err != nil {
+		return nil, "", err
+	}
+	plan.Prune = staged.Prune
+
+	goMod, err = planStagedModFiles(plan, staged)
+	if err != nil {
+		return nil, "", err
+	}
+	return goMod, staged.Vendor, nil
+}
+
+func planModCache(plan *bootstrap.Plan, dir, staging string, goMod []byte, opts bootstrap.VendorOptions) ([]byte, error) {
+	name, err := moduleName(goMod)
+	if err != nil {
+		return nil, err
+	}
+
+	staged, _, err := bootstrap.StageModCache(context.Background(), dir, staging, goMod, opts)
+	if err != nil {
+		return nil, err
+	}
+	tarball, err := os.ReadFile(staged.ModCache)
+	if err != nil {
+		return nil, fmt.Errorf("read staged module cache: %w", err)
+	}
+	if err := planAbsFile(plan, bootstrap.ModCachePath(dir, name), tarball); err != nil {
+		return nil, err
+	}
+
+	return planStagedModFiles(plan, staged)
+}
+
+// planStagedModFiles records the staged go.sum and returns the staged
+// go.mod for the caller to record last.
+func planStagedModFiles(plan *bootstrap.Plan, staged bootstrap.Staged) ([]byte, error) {
+	if goSum, err := os.ReadFile(staged.GoSum); err == nil {
+		if err := plan.File("go.sum", goSum); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read staged go.sum: %w", err)
+	}
+
+	// go may tidy the staged go.mod while vendoring.
+	goMod, err := os.ReadFile(staged.GoMod)
+	if err != nil {
+		return nil, fmt.Errorf("read staged go.mod: %w", err)
+	}
+	return goMod, nil
+}
+
+func planGearFile(plan *bootstrap.Plan, out gear.Output) error {
+	if out.Changed() {
+		if err := planAbsFile(plan, out.Path, out.Content); err != nil {
+			return err
+		}
+	}
+	if out.Generated != nil {
+		return planAbsFile(plan, gear.BasePath(out.Path), out.Generated)
+	}
+	return nil
+}
+
+func planAbsFile(plan *bootstrap.Plan, path string, content []byte) error {
+	rel, err := filepath.Rel(plan.Dir, path)
+	if err != nil {
+		return fmt.Errorf("plan %s: %w", path, err)
+	}
+	return plan.File(filepath.ToSlash(rel), content)
+}
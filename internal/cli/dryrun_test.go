@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reservation-v/vlang/internal/bootstrap"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+// writeProject lays out example.com/tool using a local cgo library with
+// an MIT license, replaced by ../lib so vendoring needs no network.
+func writeProject(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "tool")
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/tool\n\ngo 1.22\n\n"+
+		"require example.com/lib v0.0.0\n\nreplace example.com/lib => ../lib\n")
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n\nimport _ \"example.com/lib\"\n\nfunc main() {}\n")
+	writeFile(t, filepath.Join(root, "lib", "go.mod"), "module example.com/lib\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(root, "lib", "lib.go"), "package lib\n\n// #cgo pkg-config: sqlite3\nimport \"C\"\n")
+	writeFile(t, filepath.Join(root, "lib", "LICENSE"), mitText)
+	return dir
+}
+
+const mitText = `Copyright (c) 2024 Example
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+associated documentation files (the "Software"), to deal in the Software without restriction,
+including without limitation the rights to use, copy, modify, merge, publish, distribute,
+sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or
+substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT
+NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES
+OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+`
+
+func TestBootstrapDryRunUsesStagedTree(t *testing.T) {
+	dir := writeProject(t)
+	writeFile(t, filepath.Join(dir, "LICENSE"), "SPDX-License-Identifier: Apache-2.0\n")
+	out := filepath.Join(t.TempDir(), "plan.json")
+
+	err := RunBootstrap([]string{"-dir", dir, "-dry-run", "-go", "1.23", "-version", "v1.0.0",
+		"-packager", "Test <test@example.com>", "-format", "json", "-output", out})
+	if err != nil {
+		t.Fatalf("RunBootstrap() error: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read plan: %v", err)
+	}
+	var plan bootstrap.Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		t.Fatalf("decode plan: %v", err)
+	}
+	changes := make(map[string]bootstrap.Change)
+	for _, change := range plan.Changes {
+		changes[change.Path] = change
+	}
+
+	if change := changes["go.mod"]; !strings.Contains(change.Diff, "+go 1.23") {
+		t.Errorf("go.mod change: %+v", change)
+	}
+	if _, ok := changes["vendor/modules.txt"]; !ok {
+		t.Errorf("plan has no vendor/modules.txt: %v", plan.Changes)
+	}
+	spec, ok := changes[".gear/tool.spec"]
+	if !ok || spec.Kind != bootstrap.ChangeCreated {
+		t.Fatalf("spec change: %+v", spec)
+	}
+	for _, want := range []string{
+		"+BuildRequires: golang >= 1.23\n",
+		"+BuildRequires: pkgconfig(sqlite3)\n",
+		"+Provides: bundled(golang(example.com/lib))\n",
+		"+License: Apache-2.0 and MIT\n",
+	} {
+		if !strings.Contains(spec.Diff, want) {
+			t.Errorf("spec diff missing %q:\n%s", want, spec.Diff)
+		}
+	}
+
+	// Nothing is written to the project.
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatalf("read go.mod: %v", err)
+	}
+	if !strings.Contains(string(goMod), "go 1.22\n") {
+		t.Errorf("dry run changed go.mod:\n%s", goMod)
+	}
+	for _, name := range []string{"vendor", ".gear"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("dry run created %s: %v", name, err)
+		}
+	}
+}
@@ -10,8 +10,10 @@ import (
 )
 
 type inspectFlags struct {
-	Dir string
-	Out OutputFlags
+	Dir       string
+	VendorCgo bool
+	Provides  bool
+	Out       OutputFlags
 }
 
 func RunInspect(args []string) error {
@@ -26,10 +28,29 @@ func RunInspect(args []string) error {
 	}
 	inspectFlgs.Dir = absDir
 
+	if inspectFlgs.Provides {
+		provides, err := inspect.BundledProvides(absDir)
+		if err != nil {
+			return fmt.Errorf("inspect: %w", err)
+		}
+		writeErr := writeOutputWriter(inspectFlgs.Out.Output, func(w io.Writer) error {
+			return WriteOutputProvides(w, inspectFlgs.Out.Format, provides)
+		})
+		if writeErr != nil {
+			return fmt.Errorf("write output: %w", writeErr)
+		}
+		return nil
+	}
+
 	info, inspectErr := inspect.Inspect(absDir)
 	if inspectErr != nil {
 		return fmt.Errorf("inspect: %w", inspectErr)
 	}
+	if inspectFlgs.VendorCgo {
+		if err := inspect.AddVendorCgo(&info); err != nil {
+			return fmt.Errorf("inspect: %w", err)
+		}
+	}
 
 	writeErr := writeOutputWriter(inspectFlgs.Out.Output, func(w io.Writer) error {
 		return WriteOutputInspect(w, inspectFlgs.Out.Format, info)
@@ -46,14 +67,18 @@ func parseInspectFlags(args []string) (inspectFlags, error) {
 	fs.SetOutput(os.Stderr)
 
 	dirPtr := addDirFlag(fs)
+	vendorCgo := fs.Bool("vendor-cgo", false, "also scan vendor/ for packages using cgo")
+	provides := fs.Bool("provides", false, "print only the bundled() provides of vendor/modules.txt")
 	format, output := addOutputFlags(fs)
 	if err := fs.Parse(args); err != nil {
 		return inspectFlags{}, err
 	}
 
 	inspectFs := inspectFlags{
-		Dir: *dirPtr,
-		Out: OutputFlags{Format: *format, Output: *output},
+		Dir:       *dirPtr,
+		VendorCgo: *vendorCgo,
+		Provides:  *provides,
+		Out:       OutputFlags{Format: *format, Output: *output},
 	}
 
 	return inspectFs, nil
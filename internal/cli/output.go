@@ -4,20 +4,33 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/reservation-v/vlang/internal/bootstrap"
+	"github.com/reservation-v/vlang/internal/gear"
 	"github.com/reservation-v/vlang/internal/inspect"
+	"github.com/reservation-v/vlang/internal/license"
 	"github.com/reservation-v/vlang/internal/validate"
 )
 
 type VendorInfo struct {
-	Enabled bool   `json:"enabled"`
-	Status  string `json:"status"`
+	Enabled bool                    `json:"enabled"`
+	Status  string                  `json:"status"`
+	Result  *bootstrap.VendorResult `json:"result,omitempty"`
 }
 
-type output struct {
-	ProjectInfo bootstrap.ProjectInfo `json:"project_info"`
-	Vendor      VendorInfo            `json:"vendor"`
+type GearFileInfo struct {
+	Path   string          `json:"path,omitempty"`
+	Status gear.FileStatus `json:"status"`
+}
+
+type BootstrapOutput struct {
+	ProjectInfo bootstrap.ProjectInfo     `json:"project_info"`
+	Vendor      VendorInfo                `json:"vendor"`
+	ModCache    *bootstrap.ModCacheResult `json:"modcache,omitempty"`
+	GoModEdited bool                      `json:"go_mod_edited"`
+	Rules       GearFileInfo              `json:"rules"`
+	Spec        GearFileInfo              `json:"spec"`
 }
 
 func WriteOutputValidate(w io.Writer, format string, report validate.Report) error {
@@ -46,14 +59,58 @@ func WriteOutputInspect(w io.Writer, format string, info inspect.Info) error {
 	}
 }
 
-func WriteOutput(w io.Writer, format string, projectInfo bootstrap.ProjectInfo, vendorInfo VendorInfo) error {
+func WriteOutputProvides(w io.Writer, format string, provides []inspect.Provide) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(provides)
+	case "text":
+		for _, provide := range provides {
+			if _, err := fmt.Fprintln(w, "Provides:", provide); err != nil {
+				return fmt.Errorf("provides printer: %w", err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+func WriteOutputRPMVer(w io.Writer, format string, info RPMVerInfo) error {
 	switch format {
 	case "json":
 		enc := json.NewEncoder(w)
 		enc.SetIndent("", "  ")
-		return enc.Encode(output{projectInfo, vendorInfo})
+		return enc.Encode(info)
 	case "text":
-		err := printer(w, projectInfo, vendorInfo)
+		return printRPMVer(w, info)
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+func WriteOutputPlan(w io.Writer, format string, plan bootstrap.Plan) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	case "text":
+		return printPlan(w, plan)
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+func WriteOutput(w io.Writer, format string, out BootstrapOutput) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	case "text":
+		err := printer(w, out)
 		if err != nil {
 			return err
 		}
@@ -64,19 +121,75 @@ func WriteOutput(w io.Writer, format string, projectInfo bootstrap.ProjectInfo,
 	return nil
 }
 
-func printer(w io.Writer, projectInfo bootstrap.ProjectInfo, vendorInfo VendorInfo) error {
+func printer(w io.Writer, out BootstrapOutput) error {
 	_, err := fmt.Fprintln(w,
 		"Project Info:",
-		"\nName:", projectInfo.Name,
-		"\nDir:", projectInfo.Dir,
-		"\nModulePath:", projectInfo.ModulePath,
-		"\nImportPath:", projectInfo.ImportPath,
-		"\nVendorStatus:", vendorInfo.Status,
+		"\nName:", out.ProjectInfo.Name,
+		"\nDir:", out.ProjectInfo.Dir,
+		"\nModulePath:", out.ProjectInfo.ModulePath,
+		"\nImportPath:", out.ProjectInfo.ImportPath,
+		"\nWorkspace:", orNone(out.ProjectInfo.Workspace),
+		"\nVendorStatus:", out.Vendor.Status,
+		"\nGoModEdited:", out.GoModEdited,
+		"\nRulesStatus:", out.Rules.Status,
+		"\nSpecStatus:", out.Spec.Status,
 	)
 	if err != nil {
 		return fmt.Errorf("printer: %w", err)
 	}
 
+	if mc := out.ModCache; mc != nil {
+		_, err := fmt.Fprintf(w, "ModCache: %s (%d modules, %d files, %d bytes, changed: %t)\n",
+			mc.Path, mc.Modules, mc.Files, mc.Bytes, mc.Changed)
+		if err != nil {
+			return fmt.Errorf("printer: %w", err)
+		}
+	}
+
+	if out.Vendor.Result != nil {
+		if err := printVendorReport(w, out.Vendor.Result.Report); err != nil {
+			return fmt.Errorf("printer: %w", err)
+		}
+		if err := printPrune(w, out.Vendor.Result.Prune); err != nil {
+			return fmt.Errorf("printer: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func printPrune(w io.Writer, result *bootstrap.PruneResult) error {
+	if result == nil {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "vendor pruned: %d -> %d files, %d -> %d bytes\n",
+		result.FilesBefore, result.FilesAfter, result.BytesBefore, result.BytesAfter)
+	return err
+}
+
+func printVendorReport(w io.Writer, report bootstrap.VendorReport) error {
+	var lines []string
+	for _, mod := range report.Added {
+		lines = append(lines, fmt.Sprintf("vendor added: %s %s", mod.Path, mod.New))
+	}
+	for _, mod := range report.Removed {
+		lines = append(lines, fmt.Sprintf("vendor removed: %s %s", mod.Path, mod.Old))
+	}
+	for _, mod := range report.Upgraded {
+		lines = append(lines, fmt.Sprintf("vendor upgraded: %s %s -> %s", mod.Path, mod.Old, mod.New))
+	}
+	for _, mod := range report.Downgraded {
+		lines = append(lines, fmt.Sprintf("vendor downgraded: %s %s -> %s", mod.Path, mod.Old, mod.New))
+	}
+	for _, lic := range report.Licenses {
+		lines = append(lines, fmt.Sprintf("vendor license %s: %s (%s)", lic.Kind, lic.File, lic.Module))
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -92,6 +205,16 @@ func printInspect(w io.Writer, info inspect.Info) error {
 		"\nHasGearDir:", info.HasGearDir,
 		"\nHasGearRules:", info.HasGearRules,
 		"\nHasGearSpec:", info.HasGearSpec,
+		"\nWorkspace:", workspacePath(info.Workspace),
+		"\nModules:", modulePaths(info.Modules),
+		"\nBinaries:", binaryNames(info.Binaries),
+		"\nCgo:", cgoPackages(info.Cgo),
+		"\nBuildRequires:", orNone(strings.Join(info.BuildRequires, ", ")),
+		"\nLicense:", orNone(info.Licenses.Expression),
+		"\nLicenseFiles:", licenseFiles(info.Licenses),
+		"\nUnknownLicenses:", orNone(strings.Join(info.Licenses.Unknown, ", ")),
+		"\nBundled:", len(info.Provides),
+		"\nGit:", gitSummary(info.Git),
 	)
 	if err != nil {
 		return fmt.Errorf("inspect printer: %w", err)
@@ -100,6 +223,86 @@ func printInspect(w io.Writer, info inspect.Info) error {
 	return nil
 }
 
+func workspacePath(ws *inspect.Workspace) string {
+	if ws == nil {
+		return "none"
+	}
+	return ws.Path
+}
+
+func modulePaths(modules []inspect.Module) string {
+	paths := make([]string, 0, len(modules))
+	for _, mod := range modules {
+		if mod.Error != "" {
+			paths = append(paths, mod.RelPath+" (broken: "+mod.Error+")")
+			continue
+		}
+		paths = append(paths, mod.RelPath)
+	}
+	return orNone(strings.Join(paths, ", "))
+}
+
+func binaryNames(binaries []inspect.Binary) string {
+	names := make([]string, 0, len(binaries))
+	for _, bin := range binaries {
+		names = append(names, bin.Name+" ("+bin.ImportPath+")")
+	}
+	return orNone(strings.Join(names, ", "))
+}
+
+func cgoPackages(packages []inspect.CgoPackage) string {
+	paths := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		paths = append(paths, pkg.ImportPath)
+	}
+	return orNone(strings.Join(paths, ", "))
+}
+
+func licenseFiles(report license.Report) string {
+	files := make([]string, 0, len(report.Files))
+	for _, f := range report.Files {
+		ids := make([]string, 0, len(f.Licenses))
+		for _, m := range f.Licenses {
+			if m.Ambiguous {
+				ids = append(ids, m.ID+" only or or-later?")
+				continue
+			}
+			ids = append(ids, m.ID)
+		}
+		files = append(files, f.Path+" ("+orNone(strings.Join(ids, ", "))+")")
+	}
+	return orNone(strings.Join(files, ", "))
+}
+
+func gitSummary(g *inspect.Git) string {
+	if g == nil {
+		return "none"
+	}
+	head := g.Head
+	if len(head) > 12 {
+		head = head[:12]
+	}
+	parts := []string{orNone(g.Branch) + "@" + orNone(head)}
+	if g.Tag != "" {
+		parts = append(parts, fmt.Sprintf("tag %s+%d", g.Tag, g.CommitsSinceTag))
+	}
+	if g.Version != "" {
+		parts = append(parts, "version "+g.Version)
+	}
+	if len(g.Dirty) > 0 || len(g.Untracked) > 0 {
+		parts = append(parts, fmt.Sprintf("dirty %d, untracked %d", len(g.Dirty), len(g.Untracked)))
+	}
+	parts = append(parts, fmt.Sprintf("vendor tracked %t, .gear tracked %t", g.VendorTracked, g.GearTracked))
+	return strings.Join(parts, ", ")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
 func printValidate(w io.Writer, report validate.Report) error {
 	_, err := fmt.Fprintf(w,
 		"Validate (%s)\nVerdict: %s\nModulePath: %s\nName: %s\nIssues: %d\n",
@@ -122,3 +325,43 @@ func printValidate(w io.Writer, report validate.Report) error {
 
 	return nil
 }
+
+func printPlan(w io.Writer, plan bootstrap.Plan) error {
+	_, err := fmt.Fprintf(w, "Plan for %s: %d changes\n", plan.Dir, len(plan.Changes))
+	if err != nil {
+		return fmt.Errorf("plan printer: %w", err)
+	}
+	if plan.Vendor != nil {
+		if err := printVendorReport(w, *plan.Vendor); err != nil {
+			return fmt.Errorf("plan printer: %w", err)
+		}
+	}
+	if err := printPrune(w, plan.Prune); err != nil {
+		return fmt.Errorf("plan printer: %w", err)
+	}
+
+	for _, change := range plan.Changes {
+		if _, err := io.WriteString(w, change.Diff); err != nil {
+			return fmt.Errorf("plan printer: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func printRPMVer(w io.Writer, info RPMVerInfo) error {
+	_, err := fmt.Fprintf(w, "Upstream: %s\nVersion: %s\nRelease: %s\n",
+		info.Upstream, info.RPM.Version, info.RPM.Release)
+	if err != nil {
+		return fmt.Errorf("rpmver printer: %w", err)
+	}
+
+	if info.Newer != nil {
+		_, err = fmt.Fprintf(w, "Current: %s\nNewer: %t\n", info.Current, *info.Newer)
+		if err != nil {
+			return fmt.Errorf("rpmver printer: %w", err)
+		}
+	}
+
+	return nil
+}
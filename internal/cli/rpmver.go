@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/reservation-v/vlang/internal/rpmver"
+)
+
+type rpmverFlags struct {
+	Version string
+	Current string
+	Out     OutputFlags
+}
+
+type RPMVerInfo struct {
+	Upstream string            `json:"upstream"`
+	RPM      rpmver.RPMVersion `json:"rpm"`
+	Current  string            `json:"current,omitempty"`
+	Newer    *bool             `json:"newer,omitempty"`
+}
+
+func RunRPMVer(args []string) error {
+	rpmverFlgs, parseErr := parseRPMVerFlags(args)
+	if parseErr != nil {
+		return fmt.Errorf("rpmver parse flags: %w", parseErr)
+	}
+	if rpmverFlgs.Version == "" {
+		return fmt.Errorf("rpmver: -version is required")
+	}
+
+	mapped, mapErr := rpmver.FromGo(rpmverFlgs.Version)
+	if mapErr != nil {
+		return fmt.Errorf("map version: %w", mapErr)
+	}
+	info := RPMVerInfo{Upstream: rpmverFlgs.Version, RPM: mapped}
+
+	if rpmverFlgs.Current != "" {
+		current, currentErr := rpmver.Parse(rpmverFlgs.Current)
+		if currentErr != nil {
+			return fmt.Errorf("parse current: %w", currentErr)
+		}
+		newer := mapped.Compare(current) > 0
+		info.Current = current.String()
+		info.Newer = &newer
+	}
+
+	writeErr := writeOutputWriter(rpmverFlgs.Out.Output, func(w io.Writer) error {
+		return WriteOutputRPMVer(w, rpmverFlgs.Out.Format, info)
+	})
+	if writeErr != nil {
+		return writeErr
+	}
+
+	if info.Newer != nil && !*info.Newer {
+		return fmt.Errorf("%s does not sort after %s", mapped, info.Current)
+	}
+
+	return nil
+}
+
+func parseRPMVerFlags(args []string) (rpmverFlags, error) {
+	fs := flag.NewFlagSet("rpmver", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	version := fs.String("version", "", "upstream tag or Go module version")
+	current := fs.String("current", "", "current packaged version-release to compare against")
+	format, output := addOutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return rpmverFlags{}, err
+	}
+
+	rpmverFs := rpmverFlags{
+		Version: *version,
+		Current: *current,
+		Out:     OutputFlags{Format: *format, Output: *output},
+	}
+
+	return rpmverFs, nil
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunRPMVerCurrent(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		current string
+		newer   bool
+	}{
+		{name: "newer", version: "v1.1.0", current: "1.0.0-alt1", newer: true},
+		{name: "same", version: "v1.0.0", current: "1.0.0-alt1", newer: false},
+		{name: "older", version: "v1.0.0-rc.1", current: "1.0.0-alt1", newer: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "rpmver.json")
+			err := RunRPMVer([]string{"-version", tt.version, "-current", tt.current, "-format", "json", "-output", out})
+			if tt.newer && err != nil {
+				t.Fatalf("RunRPMVer() error: %v", err)
+			}
+			if !tt.newer && (err == nil || !strings.Contains(err.Error(), "does not sort after "+tt.current)) {
+				t.Fatalf("RunRPMVer() error = %v, want does not sort after %s", err, tt.current)
+			}
+
+			// The comparison is written out before the command fails.
+			data, readErr := os.ReadFile(out)
+			if readErr != nil {
+				t.Fatalf("read output: %v", readErr)
+			}
+			var info RPMVerInfo
+			if err := json.Unmarshal(data, &info); err != nil {
+				t.Fatalf("decode output: %v", err)
+			}
+			if info.Current != tt.current || info.Newer == nil || *info.Newer != tt.newer {
+				t.Errorf("output: got %+v", info)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/reservation-v/vlang/internal/gear"
+)
+
+type templatesFlags struct {
+	Dir       string
+	Overwrite bool
+}
+
+func RunTemplates(args []string) error {
+	templatesFlgs, err := parseTemplatesFlags(args)
+	if err != nil {
+		return fmt.Errorf("templates parse flags: %w", err)
+	}
+
+	written, err := gear.DumpTemplates(templatesFlgs.Dir, templatesFlgs.Overwrite)
+	if err != nil {
+		return fmt.Errorf("dump templates: %w", err)
+	}
+
+	paths := make([]string, 0, len(written))
+	for path := range written {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(os.Stdout, "%s %s\n", written[path], path)
+	}
+
+	return nil
+}
+
+func parseTemplatesFlags(args []string) (templatesFlags, error) {
+	fs := flag.NewFlagSet("templates", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	dir := fs.String("dir", ".gear/templates", "directory to write the built-in templates to")
+	overwrite := fs.Bool("overwrite", false, "replace templates that already exist in dir")
+	if err := fs.Parse(args); err != nil {
+		return templatesFlags{}, err
+	}
+
+	return templatesFlags{Dir: *dir, Overwrite: *overwrite}, nil
+}
@@ -10,9 +10,10 @@ import (
 )
 
 type validateFlags struct {
-	Stage string
-	Dir   string
-	Out   OutputFlags
+	Stage  string
+	Dir    string
+	Module string
+	Out    OutputFlags
 }
 
 func RunValidate(args []string) error {
@@ -25,12 +26,18 @@ func RunValidate(args []string) error {
 	if absErr != nil {
 		return fmt.Errorf("get absolute path: %w", absErr)
 	}
+	absDir, selectErr := moduleDir(absDir, validateFlgs.Module)
+	if selectErr != nil {
+		return selectErr
+	}
 	validateFlgs.Dir = absDir
 
 	var report validate.Report
 	switch validateFlgs.Stage {
 	case "pre":
 		report = validate.Pre(absDir)
+	case "vendor":
+		report = validate.Vendor(absDir)
 	default:
 		return fmt.Errorf("stage %s not supported", validateFlgs.Stage)
 	}
@@ -50,16 +57,18 @@ func parseValidateFlags(args []string) (validateFlags, error) {
 	fs.SetOutput(os.Stderr)
 
 	dirPtr := addDirFlag(fs)
+	modulePtr := addModuleFlag(fs)
 	format, output := addOutputFlags(fs)
-	stage := fs.String("stage", "", "stage name")
+	stage := fs.String("stage", "", "stage name (pre, vendor)")
 	if err := fs.Parse(args); err != nil {
 		return validateFlags{}, err
 	}
 
 	validateFs := validateFlags{
-		Dir:   *dirPtr,
-		Stage: *stage,
-		Out:   OutputFlags{Format: *format, Output: *output},
+		Dir:    *dirPtr,
+		Module: *modulePtr,
+		Stage:  *stage,
+		Out:    OutputFlags{Format: *format, Output: *output},
 	}
 
 	return validateFs, nil
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config is read from .gear/vlang.json of the packaged module. Command-line
+// flags take precedence over it.
+type Config struct {
+	TemplateDir string `json:"template_dir,omitempty"`
+	Prune       *Prune `json:"prune,omitempty"`
+}
+
+// Prune configures vendor pruning. Allow and Deny hold patterns relative
+// to vendor/; Deny extends the built-in list and Allow overrides both.
+type Prune struct {
+	Enabled bool     `json:"enabled"`
+	Allow   []string `json:"allow,omitempty"`
+	Deny    []string `json:"deny,omitempty"`
+}
+
+func Path(dir string) string {
+	return filepath.Join(dir, ".gear", "vlang.json")
+}
+
+// Load reads path, or Path(dir) when path is empty. A missing default file
+// yields the zero Config. Relative paths in the file are resolved against dir.
+func Load(dir, path string) (Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = Path(dir)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return Config{}, nil
+		}
+		return Config{}, fmt.Errorf("read config: %w", err)
+	}
+
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	if cfg.TemplateDir != "" && !filepath.IsAbs(cfg.TemplateDir) {
+		cfg.TemplateDir = filepath.Join(dir, cfg.TemplateDir)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := Load(dir, "")
+	if err != nil {
+		t.Fatalf("Load() without config file: %v", err)
+	}
+	if cfg != (Config{}) {
+		t.Fatalf("Load() without config file: got %+v, want zero", cfg)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, ".gear"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(Path(dir), []byte(`{"template_dir": ".gear/templates"}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err = Load(dir, "")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if want := filepath.Join(dir, ".gear", "templates"); cfg.TemplateDir != want {
+		t.Fatalf("TemplateDir: got %q, want %q", cfg.TemplateDir, want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"template_directory": "x"}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "explicit_missing", path: filepath.Join(dir, "missing.json")},
+		{name: "unknown_field", path: bad},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(dir, tt.path); err == nil {
+				t.Fatalf("Load() want error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadPrune(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vlang.json")
+	if err := os.WriteFile(path, []byte(`{"prune": {"enabled": true, "allow": ["x/docs"], "deny": ["*.bin"]}}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(dir, path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Prune == nil || !cfg.Prune.Enabled ||
+		len(cfg.Prune.Allow) != 1 || cfg.Prune.Allow[0] != "x/docs" ||
+		len(cfg.Prune.Deny) != 1 || cfg.Prune.Deny[0] != "*.bin" {
+		t.Fatalf("Prune: got %+v", cfg.Prune)
+	}
+}
@@ -0,0 +1,247 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const context = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// op is one line of the edit script; a and b are the line indexes in the
+// old and new text at the point the op applies.
+type op struct {
+	kind opKind
+	a, b int
+}
+
+// Unified returns a unified diff of a and b with three lines of context,
+// or "" when they are equal.
+func Unified(oldName, newName string, a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+
+	al, bl := splitLines(a), splitLines(b)
+	ops := editScript(al, bl)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].kind == opEqual {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		start := max(i-context, 0)
+		end := i
+		for {
+			for end < len(ops) && ops[end].kind != opEqual {
+				end++
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+			if next < len(ops) && next-end <= 2*context {
+				end = next
+				continue
+			}
+			end = min(end+context, next)
+			break
+		}
+
+		writeHunk(&out, ops[start:end], al, bl)
+		i = end
+	}
+
+	return out.String()
+}
+
+func writeHunk(out *strings.Builder, ops []op, a, b []string) {
+	var aCount, bCount int
+	for _, o := range ops {
+		if o.kind != opInsert {
+			aCount++
+		}
+		if o.kind != opDelete {
+			bCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(ops[0].a, aCount), hunkRange(ops[0].b, bCount))
+
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			writeLine(out, ' ', a[o.a])
+		case opDelete:
+			writeLine(out, '-', a[o.a])
+		case opInsert:
+			writeLine(out, '+', b[o.b])
+		}
+	}
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func writeLine(out *strings.Builder, prefix byte, line string) {
+	out.WriteByte(prefix)
+	out.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		out.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+func splitLines(data []byte) []string {
+	var lines []string
+	s := string(data)
+	for s != "" {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			lines = append(lines, s)
+			break
+		}
+		lines = append(lines, s[:i+1])
+		s = s[i+1:]
+	}
+	return lines
+}
+
+// editScript is Myers' O(ND) diff in its linear-space form: the middle
+// snake of the shortest edit script splits the texts in two and each half
+// is diffed on its own, so memory stays O(N+M) however many lines differ.
+func editScript(a, b []string) []op {
+	return diffRange(make([]op, 0, max(len(a), len(b))), a, b, 0, len(a), 0, len(b))
+}
+
+// diffRange appends the edit script of a[a0:a1] against b[b0:b1] to ops.
+func diffRange(ops []op, a, b []string, a0, a1, b0, b1 int) []op {
+	for a0 < a1 && b0 < b1 && a[a0] == b[b0] {
+		ops = append(ops, op{kind: opEqual, a: a0, b: b0})
+		a0++
+		b0++
+	}
+	suffix := 0
+	for a1-suffix > a0 && b1-suffix > b0 && a[a1-suffix-1] == b[b1-suffix-1] {
+		suffix++
+	}
+	a1 -= suffix
+	b1 -= suffix
+
+	if x, y, ok := middleSnake(a, b, a0, a1, b0, b1); ok && (x-a0)+(y-b0) > 0 && (a1-x)+(b1-y) > 0 {
+		ops = diffRange(ops, a, b, a0, x, b0, y)
+		ops = diffRange(ops, a, b, x, a1, y, b1)
+	} else {
+		// Created, deleted, or nothing in common.
+		for i := a0; i < a1; i++ {
+			ops = append(ops, op{kind: opDelete, a: i, b: b0})
+		}
+		for j := b0; j < b1; j++ {
+			ops = append(ops, op{kind: opInsert, a: a1, b: j})
+		}
+	}
+
+	for i := 0; i < suffix; i++ {
+		ops = append(ops, op{kind: opEqual, a: a1 + i, b: b1 + i})
+	}
+	return ops
+}
+
+// middleSnake runs the search from both ends of a[a0:a1] and b[b0:b1] at
+// once and returns where the two paths meet. ok is false when either side
+// is empty or the texts have no line in common.
+func middleSnake(a, b []string, a0, a1, b0, b1 int) (x, y int, ok bool) {
+	n, m := a1-a0, b1-b0
+	if n == 0 || m == 0 {
+		return 0, 0, false
+	}
+	maxD := (n + m + 1) / 2
+	off := maxD + 1
+	// fwd[k] is the furthest x on diagonal k from the start, back[k] the
+	// furthest distance from the end on diagonal k of the reversed texts.
+	fwd := make([]int, 2*off+1)
+	back := make([]int, 2*off+1)
+	for i := range fwd {
+		fwd[i], back[i] = -1, -1
+	}
+	fwd[off+1], back[off+1] = 0, 0
+
+	delta := n - m
+	front := delta%2 != 0
+	var k1start, k1end, k2start, k2end int
+	for d := 0; d < maxD; d++ {
+		for k := -d + k1start; k <= d-k1end; k += 2 {
+			var x1 int
+			if k == -d || (k != d && fwd[off+k-1] < fwd[off+k+1]) {
+				x1 = fwd[off+k+1]
+			} else {
+				x1 = fwd[off+k-1] + 1
+			}
+			y1 := x1 - k
+			for x1 < n && y1 < m && a[a0+x1] == b[b0+y1] {
+				x1++
+				y1++
+			}
+			fwd[off+k] = x1
+			switch {
+			case x1 > n:
+				k1end += 2
+			case y1 > m:
+				k1start += 2
+			case front:
+				if kb := off + delta - k; kb >= 0 && kb < len(back) && back[kb] != -1 && x1 >= n-back[kb] {
+					return a0 + x1, b0 + y1, true
+				}
+			}
+		}
+
+		for k := -d + k2start; k <= d-k2end; k += 2 {
+			var x2 int
+			if k == -d || (k != d && back[off+k-1] < back[off+k+1]) {
+				x2 = back[off+k+1]
+			} else {
+				x2 = back[off+k-1] + 1
+			}
+			y2 := x2 - k
+			for x2 < n && y2 < m && a[a1-x2-1] == b[b1-y2-1] {
+				x2++
+				y2++
+			}
+			back[off+k] = x2
+			switch {
+			case x2 > n:
+				k2end += 2
+			case y2 > m:
+				k2start += 2
+			case !front:
+				if kf := off + delta - k; kf >= 0 && kf < len(fwd) && fwd[kf] != -1 {
+					x1 := fwd[kf]
+					y1 := x1 - (kf - off)
+					if x1 >= n-x2 {
+						return a0 + x1, b0 + y1, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,187 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "equal", a: "a\nb\n", b: "a\nb\n", want: ""},
+		{
+			name: "create",
+			a:    "",
+			b:    "one\ntwo\n",
+			want: "--- a/f\n+++ b/f\n@@ -0,0 +1,2 @@\n+one\n+two\n",
+		},
+		{
+			name: "delete",
+			a:    "one\n",
+			b:    "",
+			want: "--- a/f\n+++ b/f\n@@ -1 +0,0 @@\n-one\n",
+		},
+		{
+			name: "modify_middle",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- a/f\n+++ b/f\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "two_hunks",
+			a:    "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			b:    "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+		},
+		{
+			name: "merged_hunks",
+			a:    "a\n1\n2\nb\n",
+			b:    "A\n1\n2\nB\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n-b\n+B\n",
+		},
+		{
+			name: "no_trailing_newline",
+			a:    "x\ny",
+			b:    "x\ny\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n x\n-y\n\\ No newline at end of file\n+y\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("a/f", "b/f", []byte(tt.a), []byte(tt.b))
+			if got != tt.want {
+				t.Fatalf("Unified():\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditScriptRoundTrip(t *testing.T) {
+	a := splitLines([]byte("the\nquick\nbrown\nfox\njumps\nover\nthe\nlazy\ndog\n"))
+	b := splitLines([]byte("a\nquick\nred\nfox\nleaps\nover\nthe\ndog\nagain\n"))
+
+	var gotA, gotB []string
+	for _, o := range editScript(a, b) {
+		if o.kind != opInsert {
+			gotA = append(gotA, a[o.a])
+		}
+		if o.kind != opDelete {
+			gotB = append(gotB, b[o.b])
+		}
+		if o.kind == opEqual && a[o.a] != b[o.b] {
+			t.Fatalf("equal op pairs %q with %q", a[o.a], b[o.b])
+		}
+	}
+	if len(gotA) != len(a) || len(gotB) != len(b) {
+		t.Fatalf("edit script does not cover inputs: %d/%d, %d/%d", len(gotA), len(a), len(gotB), len(b))
+	}
+	for i := range a {
+		if gotA[i] != a[i] {
+			t.Fatalf("old line %d: got %q, want %q", i, gotA[i], a[i])
+		}
+	}
+	for i := range b {
+		if gotB[i] != b[i] {
+			t.Fatalf("new line %d: got %q, want %q", i, gotB[i], b[i])
+		}
+	}
+}
+
+// checkScript verifies that ops turns a into b and returns its number of
+// inserted and deleted lines.
+func checkScript(t *testing.T, a, b []string, ops []op) int {
+	t.Helper()
+	var gotA, gotB []string
+	edits := 0
+	for _, o := range ops {
+		if o.kind != opInsert {
+			gotA = append(gotA, a[o.a])
+		}
+		if o.kind != opDelete {
+			gotB = append(gotB, b[o.b])
+		}
+		if o.kind != opEqual {
+			edits++
+		}
+	}
+	if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+		t.Fatalf("edit script does not turn %q into %q", a, b)
+	}
+	return edits
+}
+
+func TestEditScriptMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(12))
+		for i := range lines {
+			lines[i] = string(rune('a'+rng.Intn(4))) + "\n"
+		}
+		return lines
+	}
+	for i := 0; i < 2000; i++ {
+		a, b := randomLines(), randomLines()
+		got := checkScript(t, a, b, editScript(a, b))
+		if want := len(a) + len(b) - 2*lcs(a, b); got != want {
+			t.Fatalf("editScript(%q, %q): %d edits, want %d", a, b, got, want)
+		}
+	}
+}
+
+func lcs(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestEditScriptLargeInputs(t *testing.T) {
+	const n = 20000
+	var old, created strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&old, "line %d\n", i)
+		fmt.Fprintf(&created, "new %d\n", i)
+	}
+	// Every other line changed: the edit script is as long as it gets.
+	var rewritten strings.Builder
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			fmt.Fprintf(&rewritten, "changed %d\n", i)
+		} else {
+			fmt.Fprintf(&rewritten, "line %d\n", i)
+		}
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	got := Unified("/dev/null", "b/f", nil, []byte(created.String()))
+	if !strings.HasPrefix(got, "--- /dev/null\n+++ b/f\n@@ -0,0 +1,20000 @@\n+new 0\n") || strings.Count(got, "@@") != 2 {
+		t.Fatalf("Unified() of a created file: %.200s", got)
+	}
+	a, b := splitLines([]byte(old.String())), splitLines([]byte(rewritten.String()))
+	if edits := checkScript(t, a, b, editScript(a, b)); edits != n {
+		t.Fatalf("rewrite: %d edits, want %d", edits, n)
+	}
+
+	runtime.ReadMemStats(&after)
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 64<<20 {
+		t.Fatalf("diffing %d lines allocated %d MiB", n, alloc>>20)
+	}
+}
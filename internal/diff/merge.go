@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+)
+
+const (
+	ConflictStart  = "<<<<<<< "
+	conflictBase   = "||||||| "
+	conflictMiddle = "======="
+	conflictEnd    = ">>>>>>> "
+)
+
+// Labels name the sides of a conflict in the emitted markers.
+type Labels struct {
+	Ours, Base, Theirs string
+}
+
+// Merge3 merges the changes base→ours and base→theirs line by line, diff3
+// style. Regions changed differently on both sides are emitted between
+// conflict markers; the second result is the number of such regions.
+func Merge3(base, ours, theirs []byte, labels Labels) ([]byte, int) {
+	bl, ol, tl := splitLines(base), splitLines(ours), splitLines(theirs)
+	toOurs := matches(bl, ol)
+	toTheirs := matches(bl, tl)
+
+	var out bytes.Buffer
+	conflicts := 0
+	b, o, t := 0, 0, 0
+	for b < len(bl) || o < len(ol) || t < len(tl) {
+		if b < len(bl) && toOurs[b] == o && toTheirs[b] == t {
+			out.WriteString(bl[b])
+			b, o, t = b+1, o+1, t+1
+			continue
+		}
+
+		nb, no, nt := len(bl), len(ol), len(tl)
+		for i := b; i < len(bl); i++ {
+			if toOurs[i] >= 0 && toTheirs[i] >= 0 {
+				nb, no, nt = i, toOurs[i], toTheirs[i]
+				break
+			}
+		}
+
+		baseChunk, oursChunk, theirsChunk := bl[b:nb], ol[o:no], tl[t:nt]
+		switch {
+		case slices.Equal(oursChunk, baseChunk):
+			writeLines(&out, theirsChunk)
+		case slices.Equal(theirsChunk, baseChunk), slices.Equal(oursChunk, theirsChunk):
+			writeLines(&out, oursChunk)
+		default:
+			conflicts++
+			writeMarker(&out, ConflictStart+labels.Ours)
+			writeLines(&out, oursChunk)
+			writeMarker(&out, conflictBase+labels.Base)
+			writeLines(&out, baseChunk)
+			writeMarker(&out, conflictMiddle)
+			writeLines(&out, theirsChunk)
+			writeMarker(&out, conflictEnd+labels.Theirs)
+		}
+		b, o, t = nb, no, nt
+	}
+
+	return out.Bytes(), conflicts
+}
+
+// HasConflict reports whether data contains a conflict start marker.
+func HasConflict(data []byte) bool {
+	for _, line := range splitLines(data) {
+		if strings.HasPrefix(line, ConflictStart) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches maps each line of a to the line of b it is kept as, or -1.
+func matches(a, b []string) []int {
+	m := make([]int, len(a))
+	for i := range m {
+		m[i] = -1
+	}
+	for _, o := range editScript(a, b) {
+		if o.kind == opEqual {
+			m[o.a] = o.b
+		}
+	}
+	return m
+}
+
+func writeLines(out *bytes.Buffer, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+}
+
+func writeMarker(out *bytes.Buffer, marker string) {
+	if out.Len() > 0 && out.Bytes()[out.Len()-1] != '\n' {
+		out.WriteByte('\n')
+	}
+	out.WriteString(marker + "\n")
+}
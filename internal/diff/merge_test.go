@@ -0,0 +1,77 @@
+package diff
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	labels := Labels{Ours: "current", Base: "previous", Theirs: "generated"}
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		wantConflicts      int
+	}{
+		{
+			name:   "unchanged",
+			base:   "a\nb\n",
+			ours:   "a\nb\n",
+			theirs: "a\nb\n",
+			want:   "a\nb\n",
+		},
+		{
+			name:   "only_theirs",
+			base:   "Version: 1.0\nRelease: alt1\n",
+			ours:   "Version: 1.0\nRelease: alt1\n",
+			theirs: "Version: 1.1\nRelease: alt1\n",
+			want:   "Version: 1.1\nRelease: alt1\n",
+		},
+		{
+			name:   "disjoint_edits",
+			base:   "Name: x\nVersion: 1.0\n\n%build\nmake\n\n%files\n",
+			ours:   "Name: x\nVersion: 1.0\n\n%build\nmake check\n\n%files\n",
+			theirs: "Name: x\nVersion: 1.1\n\n%build\nmake\n\n%files\n",
+			want:   "Name: x\nVersion: 1.1\n\n%build\nmake check\n\n%files\n",
+		},
+		{
+			name:   "same_edit_both_sides",
+			base:   "a\nb\n",
+			ours:   "a\nB\n",
+			theirs: "a\nB\n",
+			want:   "a\nB\n",
+		},
+		{
+			name:   "insert_at_end",
+			base:   "a\n",
+			ours:   "a\nmine\n",
+			theirs: "a\n",
+			want:   "a\nmine\n",
+		},
+		{
+			name:          "conflict",
+			base:          "a\nVersion: 1.0\nz\n",
+			ours:          "a\nVersion: 1.0.1\nz\n",
+			theirs:        "a\nVersion: 1.1\nz\n",
+			want:          "a\n<<<<<<< current\nVersion: 1.0.1\n||||||| previous\nVersion: 1.0\n=======\nVersion: 1.1\n>>>>>>> generated\nz\n",
+			wantConflicts: 1,
+		},
+		{
+			name:          "conflict_without_trailing_newline",
+			base:          "a",
+			ours:          "b",
+			theirs:        "c",
+			want:          "<<<<<<< current\nb\n||||||| previous\na\n=======\nc\n>>>>>>> generated\n",
+			wantConflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Merge3([]byte(tt.base), []byte(tt.ours), []byte(tt.theirs), labels)
+			if string(got) != tt.want || conflicts != tt.wantConflicts {
+				t.Fatalf("Merge3(): got %d conflicts\n%s\nwant %d conflicts\n%s", conflicts, got, tt.wantConflicts, tt.want)
+			}
+			if HasConflict(got) != (tt.wantConflicts > 0) {
+				t.Fatalf("HasConflict(): got %v", HasConflict(got))
+			}
+		})
+	}
+}
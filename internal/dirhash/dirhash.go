@@ -0,0 +1,91 @@
+package dirhash
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Hash1 implements the "h1:" hash used in go.sum: a SHA-256 over a sorted
+// summary of "<sha256 hex>  <name>\n" lines, one per file.
+func Hash1(files []string, open func(string) (io.ReadCloser, error)) (string, error) {
+	h := sha256.New()
+	sorted := slices.Clone(files)
+	slices.Sort(sorted)
+
+	for _, file := range sorted {
+		if strings.Contains(file, "\n") {
+			return "", fmt.Errorf("file name %q contains newline", file)
+		}
+
+		r, openErr := open(file)
+		if openErr != nil {
+			return "", fmt.Errorf("open %s: %w", file, openErr)
+		}
+		fh := sha256.New()
+		_, copyErr := io.Copy(fh, r)
+		r.Close()
+		if copyErr != nil {
+			return "", fmt.Errorf("read %s: %w", file, copyErr)
+		}
+
+		fmt.Fprintf(h, "%x  %s\n", fh.Sum(nil), file)
+	}
+
+	return "h1:" + base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashDir hashes every regular file under dir, naming each one
+// prefix + "/" + its slash-separated path relative to dir. For a module
+// extracted into the module cache prefix is "path@version".
+func HashDir(dir, prefix string) (string, error) {
+	files, err := DirFiles(dir, prefix)
+	if err != nil {
+		return "", err
+	}
+
+	return Hash1(files, func(name string) (io.ReadCloser, error) {
+		rel := strings.TrimPrefix(name, prefix+"/")
+		return os.Open(filepath.Join(dir, filepath.FromSlash(rel)))
+	})
+}
+
+func HashGoMod(data []byte) (string, error) {
+	return Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(string(data))), nil
+	})
+}
+
+func DirFiles(dir, prefix string) ([]string, error) {
+	var files []string
+
+	walkErr := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", path)
+		}
+
+		rel, relErr := filepath.Rel(dir, path)
+		if relErr != nil {
+			return relErr
+		}
+		files = append(files, prefix+"/"+filepath.ToSlash(rel))
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("walk %s: %w", dir, walkErr)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,68 @@
+package dirhash
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHash1(t *testing.T) {
+	files := map[string]string{
+		"example.com/m@v1.0.0/b.go": "package m\n",
+		"example.com/m@v1.0.0/a.go": "package m\n\nfunc A() {}\n",
+	}
+	names := []string{"example.com/m@v1.0.0/b.go", "example.com/m@v1.0.0/a.go"}
+
+	got, err := Hash1(names, func(name string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(files[name])), nil
+	})
+	if err != nil {
+		t.Fatalf("Hash1() unexpected error: %v", err)
+	}
+
+	// sha256 of the summary
+	// "<sha256(a.go)>  example.com/m@v1.0.0/a.go\n<sha256(b.go)>  example.com/m@v1.0.0/b.go\n"
+	want := "h1:E1hC3QRlraXaVKJ+JiwKgTwXJqMTSq0l6XKYcb2WlOU="
+	if got != want {
+		t.Errorf("Hash1() got %q, want %q", got, want)
+	}
+
+	if _, err := Hash1([]string{"bad\nname"}, nil); err == nil {
+		t.Errorf("Hash1() with newline in name: want error, got nil")
+	}
+}
+
+func TestHashGoMod(t *testing.T) {
+	// go.sum: github.com/jstemmer/go-junit-report/v2 v2.1.0/go.mod
+	goMod := "module github.com/jstemmer/go-junit-report/v2\n\ngo 1.13\n\nrequire github.com/google/go-cmp v0.5.8\n"
+	got, err := HashGoMod([]byte(goMod))
+	if err != nil {
+		t.Fatalf("HashGoMod() unexpected error: %v", err)
+	}
+	if want := "h1:mgHVr7VUo5Tn8OLVr1cKnLuEy0M92wdRntM99h7RkgQ="; got != want {
+		t.Errorf("HashGoMod() got %q, want %q", got, want)
+	}
+}
+
+func TestHashDirMatchesHash1(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.go"), []byte("package m\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package m\n\nfunc A() {}\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	got, err := HashDir(dir, "example.com/m@v1.0.0")
+	if err != nil {
+		t.Fatalf("HashDir() unexpected error: %v", err)
+	}
+	if want := "h1:E1hC3QRlraXaVKJ+JiwKgTwXJqMTSq0l6XKYcb2WlOU="; got != want {
+		t.Errorf("HashDir() got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package gear
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/reservation-v/vlang/internal/diff"
+)
+
+var mergeLabels = diff.Labels{Ours: "current", Base: "previously generated", Theirs: "generated"}
+
+// BasePath is where the last generated version of path is recorded:
+// .gear/.vlang/<file> for .gear/<file>.
+func BasePath(path string) string {
+	return filepath.Join(filepath.Dir(path), ".vlang", filepath.Base(path))
+}
+
+// planMerge three-way merges existing with generated against the recorded
+// previous generation. ok is false when nothing was recorded.
+func planMerge(path string, existing, generated []byte) (out Output, ok bool, err error) {
+	base, readErr := os.ReadFile(BasePath(path))
+	if os.IsNotExist(readErr) {
+		return Output{}, false, nil
+	}
+	if readErr != nil {
+		return Output{}, false, fmt.Errorf("read %s: %w", BasePath(path), readErr)
+	}
+
+	out = Output{Path: path, Content: existing, Generated: generated}
+	switch {
+	case bytes.Equal(base, generated):
+		out.Status = FileKept
+		out.Generated = nil
+	case bytes.Equal(existing, generated):
+		out.Status = FileKept
+	case bytes.Equal(existing, base):
+		out.Status = FileUpdated
+		out.Content = generated
+	default:
+		merged, conflicts := diff.Merge3(base, existing, generated, mergeLabels)
+		out.Content = merged
+		out.Status = FileMerged
+		if conflicts > 0 {
+			out.Status = FileConflict
+		}
+	}
+
+	return out, true, nil
+}
@@ -0,0 +1,124 @@
+package gear
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type FileStatus string
+
+const (
+	FileCreated FileStatus = "created"
+	FileKept    FileStatus = "kept"
+	FileUpdated FileStatus = "updated"
+	FileSkipped FileStatus = "skipped"
+	// FileMerged and FileConflict mean local edits were three-way merged
+	// with the new generation, cleanly or with conflict markers.
+	FileMerged   FileStatus = "merged"
+	FileConflict FileStatus = "conflict"
+)
+
+const generatedMarker = "# Generated by vlang"
+
+type RulesOptions struct {
+	TagPrefix     string
+	VendorTarball bool
+	// ModCache ships dependencies as the module cache tarball
+	// .gear/<name>-modcache.tar instead of vendor/.
+	ModCache bool
+}
+
+// Output is a rendered file and what writing it would do. Generated, when
+// set, is recorded at BasePath(Path) for the next three-way merge.
+type Output struct {
+	Path      string
+	Content   []byte
+	Generated []byte
+	Status    FileStatus
+	// Unmanaged is set when Path is kept because it differs from the
+	// generation and vlang has no record of writing it.
+	Unmanaged bool
+}
+
+// Changed reports whether writing o modifies Path.
+func (o Output) Changed() bool {
+	switch o.Status {
+	case FileCreated, FileUpdated, FileMerged, FileConflict:
+		return true
+	}
+	return false
+}
+
+func (o Output) Write() error {
+	if o.Changed() {
+		if err := writeFile(o.Path, o.Content); err != nil {
+			return err
+		}
+	}
+	if o.Generated != nil {
+		return writeFile(BasePath(o.Path), o.Generated)
+	}
+	return nil
+}
+
+// PlanRules renders .gear/rules: the upstream tree is packed from the
+// version tag, local changes on top of it become a patch, and with
+// VendorTarball the vendor/ directory (or with ModCache the module cache
+// tarball) is shipped as a separate source.
+func PlanRules(dir string, tmpl Templates, data Data) (Output, error) {
+	content, err := tmpl.Render("rules", data)
+	if err != nil {
+		return Output{}, err
+	}
+	return PlanGenerated(filepath.Join(dir, ".gear", "rules"), content)
+}
+
+func WriteRules(dir string, tmpl Templates, data Data) (FileStatus, error) {
+	out, err := PlanRules(dir, tmpl, data)
+	if err != nil {
+		return "", err
+	}
+	return out.Status, out.Write()
+}
+
+// PlanGenerated decides what writing content to path does. Local edits
+// are merged when the previous generation was recorded; otherwise an
+// existing file is replaced only if vlang generated it.
+func PlanGenerated(path string, content []byte) (Output, error) {
+	out := Output{Path: path, Content: content, Generated: content}
+
+	existing, readErr := os.ReadFile(path)
+	switch {
+	case readErr == nil:
+		merged, ok, err := planMerge(path, existing, content)
+		if err != nil || ok {
+			return merged, err
+		}
+		if bytes.Equal(existing, content) || !strings.HasPrefix(string(existing), generatedMarker) {
+			out.Status = FileKept
+			out.Generated = nil
+			out.Unmanaged = !bytes.Equal(existing, content)
+		} else {
+			out.Status = FileUpdated
+		}
+	case os.IsNotExist(readErr):
+		out.Status = FileCreated
+	default:
+		return Output{}, fmt.Errorf("read %s: %w", path, readErr)
+	}
+
+	return out, nil
+}
+
+func writeFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package gear
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reservation-v/vlang/internal/inspect"
+)
+
+func rulesData(name string, opts RulesOptions) Data {
+	return Data{Project: inspect.Info{Name: name}, Rules: opts}
+}
+
+func TestRenderRules(t *testing.T) {
+	tests := []struct {
+		name string
+		data Data
+		want string
+	}{
+		{
+			name: "in_tree_vendor",
+			data: rulesData("vlang", RulesOptions{TagPrefix: "v"}),
+			want: generatedMarker + "; local edits are merged on the next bootstrap.\n" +
+				"spec: .gear/vlang.spec\n" +
+				"tar: v@version@:. name=@name@-@version@\n" +
+				"diff: v@version@:. . name=@name@-@version@-alt.patch\n",
+		},
+		{
+			name: "vendor_tarball_no_prefix",
+			data: rulesData("tool", RulesOptions{VendorTarball: true}),
+			want: generatedMarker + "; local edits are merged on the next bootstrap.\n" +
+				"spec: .gear/tool.spec\n" +
+				"tar: @version@:. name=@name@-@version@ exclude=vendor\n" +
+				"tar: vendor name=@name@-@version@-vendor base=vendor\n" +
+				"diff: @version@:. . name=@name@-@version@-alt.patch exclude=vendor\n",
+		},
+		{
+			name: "modcache",
+			data: rulesData("tool", RulesOptions{ModCache: true}),
+			want: generatedMarker + "; local edits are merged on the next bootstrap.\n" +
+				"spec: .gear/tool.spec\n" +
+				"tar: @version@:. name=@name@-@version@\n" +
+				"copy: .gear/tool-modcache.tar\n" +
+				"diff: @version@:. . name=@name@-@version@-alt.patch exclude=.gear/tool-modcache.tar\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Templates{}.Render("rules", tt.data)
+			if err != nil {
+				t.Fatalf("Render(rules) error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("Render(rules):\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRules(t *testing.T) {
+	dir := t.TempDir()
+	data := rulesData("vlang", RulesOptions{TagPrefix: "v"})
+	rulesPath := filepath.Join(dir, ".gear", "rules")
+
+	status, err := WriteRules(dir, Templates{}, data)
+	if err != nil || status != FileCreated {
+		t.Fatalf("first WriteRules(): got %q, %v; want %q", status, err, FileCreated)
+	}
+
+	status, err = WriteRules(dir, Templates{}, data)
+	if err != nil || status != FileKept {
+		t.Fatalf("second WriteRules(): got %q, %v; want %q", status, err, FileKept)
+	}
+
+	data.Rules.VendorTarball = true
+	status, err = WriteRules(dir, Templates{}, data)
+	if err != nil || status != FileUpdated {
+		t.Fatalf("changed WriteRules(): got %q, %v; want %q", status, err, FileUpdated)
+	}
+	content, _ := os.ReadFile(rulesPath)
+	if !strings.Contains(string(content), "-vendor base=vendor") {
+		t.Fatalf("rules not updated:\n%s", content)
+	}
+
+	manual := "spec: .gear/vlang.spec\ntar: .\n"
+	if err := os.WriteFile(rulesPath, []byte(manual), 0o644); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+	status, err = WriteRules(dir, Templates{}, data)
+	if err != nil || status != FileKept {
+		t.Fatalf("manual WriteRules(): got %q, %v; want %q", status, err, FileKept)
+	}
+	content, _ = os.ReadFile(rulesPath)
+	if string(content) != manual {
+		t.Fatalf("hand-written rules were modified:\n%s", content)
+	}
+
+	if err := os.Remove(BasePath(rulesPath)); err != nil {
+		t.Fatalf("remove recorded generation: %v", err)
+	}
+	out, err := PlanRules(dir, Templates{}, data)
+	if err != nil || out.Status != FileKept || !out.Unmanaged {
+		t.Fatalf("PlanRules() without a recorded generation: got %q, unmanaged %t, %v", out.Status, out.Unmanaged, err)
+	}
+}
@@ -0,0 +1,284 @@
+package gear
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/reservation-v/vlang/internal/license"
+)
+
+const (
+	defaultGroup    = "Development/Other"
+	defaultLicense  = "Unknown"
+	defaultPackager = "Unknown Packager <nobody@altlinux.org>"
+)
+
+type SpecOptions struct {
+	Upstream string
+	Version  string
+	Release  string
+	Summary  string
+	Licenses []string
+	Group    string
+	URL      string
+	Packager string
+	Date     time.Time
+	Docs     []string
+	Binaries []string
+
+	// Change is the text of the new %changelog entry.
+	Change string
+
+	// LicenseFiles are shipped as %license: those of the project and of
+	// its vendored modules.
+	LicenseFiles []string
+}
+
+func specDefaults(data Data) Data {
+	opts := &data.Spec
+	if opts.Release == "" {
+		opts.Release = "alt1"
+	}
+	if opts.Summary == "" {
+		opts.Summary = fmt.Sprintf("%s built from %s", data.Project.Name, data.Project.ImportPath)
+	}
+	if opts.Group == "" {
+		opts.Group = defaultGroup
+	}
+	if opts.URL == "" {
+		opts.URL = "https://" + data.Project.ImportPath
+	}
+	if opts.Packager == "" {
+		opts.Packager = defaultPackager
+	}
+	if opts.Date.IsZero() {
+		opts.Date = time.Now()
+	}
+	if opts.Change == "" {
+		opts.Change = "Initial build for ALT Sisyphus."
+	}
+	// Combined leaves out what Unknown lists, so the tag would miss a
+	// license; the packager has to name them then.
+	if len(opts.Licenses) == 0 && len(data.Project.Licenses.Unknown) == 0 {
+		opts.Licenses = data.Project.Licenses.Combined
+	}
+	if len(opts.LicenseFiles) == 0 {
+		for _, file := range data.Project.Licenses.Files {
+			opts.LicenseFiles = append(opts.LicenseFiles, file.Path)
+		}
+		for _, mod := range data.Project.Licenses.Modules {
+			for _, file := range mod.Files {
+				opts.LicenseFiles = append(opts.LicenseFiles, file.Path)
+			}
+		}
+	}
+	if len(opts.Binaries) == 0 {
+		for _, bin := range data.Project.Binaries {
+			opts.Binaries = append(opts.Binaries, bin.Name)
+		}
+	}
+	return data
+}
+
+// RenderSpec builds an ALT golang spec matching the layout of the rules
+// template: the source tarball, the local patch and, with VendorTarball,
+// the vendor tarball unpacked into the source tree by %setup -a1.
+func RenderSpec(tmpl Templates, data Data) ([]byte, error) {
+	return tmpl.Render("spec", specDefaults(data))
+}
+
+// PlanSpec renders .gear/<name>.spec. Unlike rules, a spec is edited by
+// hand after the first bootstrap: an existing one is merged with the new
+// generation when the previous one was recorded, kept otherwise, and
+// replaced only when overwrite is set.
+//
+// The %changelog entries are history rather than generated text: they
+// are kept as they are, and the rendered entry is put on top of them when
+// the version-release changes.
+func PlanSpec(dir string, tmpl Templates, data Data, overwrite bool) (Output, error) {
+	path := SpecPath(dir, data.Project.Name)
+	existing, readErr := os.ReadFile(path)
+	if readErr != nil && !os.IsNotExist(readErr) {
+		return Output{}, fmt.Errorf("read %s: %w", path, readErr)
+	}
+	if readErr == nil && data.Spec.Change == "" {
+		data.Spec.Change = "New version " + data.Spec.Version + "."
+	}
+
+	content, err := RenderSpec(tmpl, data)
+	if err != nil {
+		return Output{}, err
+	}
+	if readErr != nil {
+		existing = nil
+	}
+	return planSpecFile(path, existing, content, overwrite)
+}
+
+// planSpecFile plans content over existing, nil when there is no spec.
+func planSpecFile(path string, existing, content []byte, overwrite bool) (Output, error) {
+	head, entry, _ := splitChangelog(content)
+	out := Output{Path: path, Content: content, Generated: head}
+	if existing == nil {
+		out.Status = FileCreated
+		return out, nil
+	}
+
+	existingHead, history, hasChangelog := splitChangelog(existing)
+	if overwrite {
+		out.Status = FileUpdated
+		out.Content = joinChangelog(head, entry, history)
+		return out, nil
+	}
+	merged, ok, err := planMerge(path, existingHead, head)
+	if err != nil {
+		return Output{}, err
+	}
+	if !ok {
+		if bytes.Equal(existingHead, head) {
+			// Nothing to lose: record it so later versions are merged.
+			return Output{Path: path, Content: existing, Generated: head, Status: FileKept}, nil
+		}
+		return Output{Path: path, Content: existing, Status: FileKept, Unmanaged: true}, nil
+	}
+	if hasChangelog {
+		merged.Content = joinChangelog(merged.Content, entry, history)
+	}
+	if merged.Status == FileKept && !bytes.Equal(merged.Content, existing) {
+		merged.Status = FileUpdated
+	}
+	return merged, nil
+}
+
+const changelogSection = "%changelog\n"
+
+// splitChangelog cuts spec after its %changelog line, into the text
+// bootstrap generates and the entries below it. ok is false when spec has
+// no %changelog section.
+func splitChangelog(spec []byte) (head, entries []byte, ok bool) {
+	i := bytes.Index(spec, []byte("\n"+changelogSection))
+	if i < 0 {
+		return spec, nil, false
+	}
+	i += 1 + len(changelogSection)
+	return spec[:i], spec[i:], true
+}
+
+// joinChangelog puts head back over history, with entry on top when it is
+// for another version-release than the latest entry of history.
+func joinChangelog(head, entry, history []byte) []byte {
+	out := slices.Clone(head)
+	entry = bytes.TrimRight(entry, "\n")
+	if len(entry) > 0 && changelogEVR(entry) != changelogEVR(history) {
+		out = append(out, entry...)
+		out = append(out, '\n')
+		if len(bytes.TrimSpace(history)) > 0 {
+			out = append(out, '\n')
+		}
+	}
+	return append(out, history...)
+}
+
+// changelogEVR is the version-release that ends the header line of the
+// first entry in entries.
+func changelogEVR(entries []byte) string {
+	header, _, _ := bytes.Cut(bytes.TrimLeft(entries, "\n"), []byte("\n"))
+	fields := strings.Fields(string(header))
+	if len(fields) < 2 || fields[0] != "*" {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// RecordedVersion reads the Version and Release tags of the spec at path,
+// from its recorded generation when there is one and from the spec itself
+// otherwise. ok is false when neither has a Version.
+func RecordedVersion(path string) (version, release string, ok bool, err error) {
+	for _, name := range []string{BasePath(path), path} {
+		data, readErr := os.ReadFile(name)
+		if os.IsNotExist(readErr) {
+			continue
+		}
+		if readErr != nil {
+			return "", "", false, fmt.Errorf("read %s: %w", name, readErr)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			tag, value, found := strings.Cut(line, ":")
+			switch {
+			case !found:
+			case strings.TrimSpace(tag) == "Version" && version == "":
+				version = strings.TrimSpace(value)
+			case strings.TrimSpace(tag) == "Release" && release == "":
+				release = strings.TrimSpace(value)
+			}
+		}
+		if version != "" {
+			return version, release, true, nil
+		}
+		release = ""
+	}
+	return "", "", false, nil
+}
+
+func WriteSpec(dir string, tmpl Templates, data Data, overwrite bool) (FileStatus, error) {
+	out, err := PlanSpec(dir, tmpl, data, overwrite)
+	if err != nil {
+		return "", err
+	}
+	return out.Status, out.Write()
+}
+
+func SpecPath(dir, name string) string {
+	return filepath.Join(dir, ".gear", name+".spec")
+}
+
+func planUnlessExists(path string, content []byte, overwrite bool) (Output, error) {
+	out := Output{Path: path, Content: content}
+
+	_, statErr := os.Stat(path)
+	switch {
+	case statErr == nil:
+		out.Status = FileKept
+		if overwrite {
+			out.Status = FileUpdated
+		}
+	case os.IsNotExist(statErr):
+		out.Status = FileCreated
+	default:
+		return Output{}, fmt.Errorf("stat %s: %w", path, statErr)
+	}
+
+	return out, nil
+}
+
+// DocFiles lists top-level README, CHANGELOG and similar files worth
+// shipping as %doc. License files go to %license instead.
+func DocFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", dir, err)
+	}
+
+	var docs []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || license.IsLicenseFile(entry.Name()) {
+			continue
+		}
+		upper := strings.ToUpper(entry.Name())
+		for _, prefix := range []string{"README", "CHANGELOG", "AUTHORS"} {
+			if strings.HasPrefix(upper, prefix) {
+				docs = append(docs, entry.Name())
+				break
+			}
+		}
+	}
+	sort.Strings(docs)
+
+	return docs, nil
+}
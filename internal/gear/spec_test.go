@@ -0,0 +1,321 @@
+package gear
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reservation-v/vlang/internal/inspect"
+	"github.com/reservation-v/vlang/internal/license"
+)
+
+func TestRenderSpec(t *testing.T) {
+	data := Data{
+		Project: inspect.Info{Name: "vlang", ImportPath: "github.com/reservation-v/vlang", GoVersion: "1.22"},
+		Spec: SpecOptions{
+			Version:  "1.2.0",
+			Licenses: []string{"MIT"},
+			Packager: "Jane Doe <jane@altlinux.org>",
+			Date:     time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC),
+			Docs:     []string{"README.md"},
+		},
+	}
+
+	got := renderSpec(t, data)
+	for _, want := range []string{
+		"%define import_path github.com/reservation-v/vlang\n",
+		"Name: vlang\nVersion: 1.2.0\nRelease: alt1\n",
+		"Summary: vlang built from github.com/reservation-v/vlang\n",
+		"License: MIT\nGroup: Development/Other\nUrl: https://github.com/reservation-v/vlang\n",
+		"BuildRequires(pre): rpm-build-golang\nBuildRequires: golang >= 1.22\n",
+		"%prep\n%setup\n%patch -p1\n",
+		"%build\nexport BUILDDIR=\"$PWD/.build\"\nexport IMPORT_PATH=\"%import_path\"\nexport GOPATH=\"$BUILDDIR:%go_path\"\n",
+		"%golang_prepare\n",
+		"%install\nexport BUILDDIR=\"$PWD/.build\"\nexport IGNORE_SOURCES=1\n%golang_install\n",
+		"%golang_build .\n",
+		"%golang_install\n",
+		"%files\n%doc README.md\n%_bindir/*\n",
+		"%changelog\n* Thu Mar 05 2026 Jane Doe <jane@altlinux.org> 1.2.0-alt1\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderSpec() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "IGNORE_SOURCES") != 1 {
+		t.Errorf("RenderSpec() exports IGNORE_SOURCES outside %%install:\n%s", got)
+	}
+	if strings.Contains(got, "Source1:") {
+		t.Errorf("RenderSpec() without VendorTarball has Source1:\n%s", got)
+	}
+
+	data.Rules.VendorTarball = true
+	got = renderSpec(t, data)
+	for _, want := range []string{"Source1: %name-%version-vendor.tar\n", "%setup -a1\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderSpec() with VendorTarball missing %q", want)
+		}
+	}
+
+	data.Rules = RulesOptions{ModCache: true}
+	got = renderSpec(t, data)
+	for _, want := range []string{"Source1: %name-modcache.tar\n", "%setup -a1\n", "export GOFLAGS=-mod=mod GOPROXY=off", `export GOMODCACHE="$PWD/modcache"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderSpec() with ModCache missing %q", want)
+		}
+	}
+	if strings.Contains(got, "-mod=vendor") {
+		t.Errorf("RenderSpec() with ModCache still builds with -mod=vendor")
+	}
+}
+
+func TestRenderSpecProjectFacts(t *testing.T) {
+	data := Data{
+		Project: inspect.Info{Name: "tool", ImportPath: "example.com/tool", Binaries: []inspect.Binary{
+			{Name: "tool", ImportPath: "example.com/tool", RelPath: "."},
+			{Name: "toolctl", ImportPath: "example.com/tool/cmd/toolctl", RelPath: "cmd/toolctl"},
+		}, BuildRequires: []string{"pkgconfig(sqlite3)", "zlib-devel"}, Licenses: license.Report{
+			Files: []license.File{{Path: "LICENSE"}, {Path: "NOTICE"}},
+			Modules: []license.Module{
+				{Path: "example.com/dep", Files: []license.File{{Path: "vendor/example.com/dep/LICENSE"}}},
+				{Path: "example.com/local"},
+			},
+			Combined: []string{"Apache-2.0", "MIT OR Apache-2.0"},
+		}, Provides: []inspect.Provide{
+			{Module: "example.com/dep", Upstream: "v1.2.0-rc.1", Version: "1.2.0~rc1"},
+			{Module: "example.com/local"},
+		}},
+		Spec: SpecOptions{Version: "1.0.0"},
+	}
+
+	got := renderSpec(t, data)
+	for _, want := range []string{
+		"BuildRequires: golang\nBuildRequires: pkgconfig(sqlite3)\nBuildRequires: zlib-devel\n\n" +
+			"Provides: bundled(golang(example.com/dep)) = 1.2.0~rc1\nProvides: bundled(golang(example.com/local))\n\n%description",
+		"License: Apache-2.0 and (MIT OR Apache-2.0)\n",
+		"%golang_build . cmd/toolctl\n",
+		"%files\n%license LICENSE NOTICE vendor/example.com/dep/LICENSE\n%_bindir/tool\n%_bindir/toolctl\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderSpec() missing %q in:\n%s", want, got)
+		}
+	}
+
+	data.Project.Licenses.Unknown = []string{"example.com/dep"}
+	if got := renderSpec(t, data); !strings.Contains(got, "License: Unknown\n") {
+		t.Errorf("License: guessed with unknown licenses:\n%s", got)
+	}
+
+	data.Spec.Licenses = []string{"BSD-3-Clause"}
+	if got := renderSpec(t, data); !strings.Contains(got, "License: BSD-3-Clause\n") {
+		t.Errorf("explicit Licenses not kept:\n%s", got)
+	}
+
+	data.Spec.Binaries = []string{"only"}
+	if got := renderSpec(t, data); !strings.Contains(got, "%license LICENSE NOTICE vendor/example.com/dep/LICENSE\n%_bindir/only\n\n") {
+		t.Errorf("explicit Binaries not kept:\n%s", got)
+	}
+}
+
+func renderSpec(t *testing.T, data Data) string {
+	t.Helper()
+	out, err := RenderSpec(Templates{}, data)
+	if err != nil {
+		t.Fatalf("RenderSpec() error: %v", err)
+	}
+	return string(out)
+}
+
+func TestWriteSpec(t *testing.T) {
+	dir := t.TempDir()
+	data := Data{
+		Project: inspect.Info{Name: "vlang", ImportPath: "example.com/vlang"},
+		Spec:    SpecOptions{Version: "1.0.0"},
+	}
+	specPath := SpecPath(dir, "vlang")
+
+	status, err := WriteSpec(dir, Templates{}, data, false)
+	if err != nil || status != FileCreated {
+		t.Fatalf("first WriteSpec(): got %q, %v; want %q", status, err, FileCreated)
+	}
+
+	manual := "Name: vlang\n# hand-tuned\n"
+	if err := os.WriteFile(specPath, []byte(manual), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	status, err = WriteSpec(dir, Templates{}, data, false)
+	if err != nil || status != FileKept {
+		t.Fatalf("WriteSpec() without overwrite: got %q, %v; want %q", status, err, FileKept)
+	}
+	content, _ := os.ReadFile(specPath)
+	if string(content) != manual {
+		t.Fatalf("existing spec was modified:\n%s", content)
+	}
+
+	status, err = WriteSpec(dir, Templates{}, data, true)
+	if err != nil || status != FileUpdated {
+		t.Fatalf("WriteSpec() with overwrite: got %q, %v; want %q", status, err, FileUpdated)
+	}
+	content, _ = os.ReadFile(specPath)
+	if !strings.Contains(string(content), "Version: 1.0.0\n") {
+		t.Fatalf("spec not overwritten:\n%s", content)
+	}
+}
+
+func TestDocFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"README.md", "LICENSE", "copying.txt", "main.go", "CHANGELOG.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "docs"), 0o755); err != nil {
+		t.Fatalf("mkdir docs: %v", err)
+	}
+
+	got, err := DocFiles(dir)
+	if err != nil {
+		t.Fatalf("DocFiles() error: %v", err)
+	}
+	want := "CHANGELOG.md README.md"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("DocFiles(): got %q, want %q", strings.Join(got, " "), want)
+	}
+}
+
+func TestWriteSpecMerge(t *testing.T) {
+	dir := t.TempDir()
+	data := Data{
+		Project: inspect.Info{Name: "vlang", ImportPath: "example.com/vlang"},
+		Spec:    SpecOptions{Version: "1.0.0", Date: time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	specPath := SpecPath(dir, "vlang")
+
+	if status, err := WriteSpec(dir, Templates{}, data, false); err != nil || status != FileCreated {
+		t.Fatalf("first WriteSpec(): got %q, %v", status, err)
+	}
+	if _, err := os.Stat(BasePath(specPath)); err != nil {
+		t.Fatalf("generated spec not recorded: %v", err)
+	}
+
+	content, _ := os.ReadFile(specPath)
+	edited := strings.Replace(string(content), "%golang_build .\n", "%golang_build ./cmd/...\n", 1)
+	if err := os.WriteFile(specPath, []byte(edited), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+
+	data.Spec.Version = "1.1.0"
+	if status, err := WriteSpec(dir, Templates{}, data, false); err != nil || status != FileMerged {
+		t.Fatalf("WriteSpec() after manual edit: got %q, %v; want %q", status, err, FileMerged)
+	}
+	content, _ = os.ReadFile(specPath)
+	for _, want := range []string{"Version: 1.1.0\n", "%golang_build ./cmd/...\n"} {
+		if !strings.Contains(string(content), want) {
+			t.Fatalf("merged spec missing %q:\n%s", want, content)
+		}
+	}
+
+	edited = strings.Replace(string(content), "Version: 1.1.0\n", "Version: 1.1.0.1\n", 1)
+	if err := os.WriteFile(specPath, []byte(edited), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	data.Spec.Version = "1.2.0"
+	if status, err := WriteSpec(dir, Templates{}, data, false); err != nil || status != FileConflict {
+		t.Fatalf("WriteSpec() with conflicting edit: got %q, %v; want %q", status, err, FileConflict)
+	}
+	content, _ = os.ReadFile(specPath)
+	if !strings.Contains(string(content), "<<<<<<< current\nVersion: 1.1.0.1\n") {
+		t.Fatalf("conflict markers missing:\n%s", content)
+	}
+	base, _ := os.ReadFile(BasePath(specPath))
+	if !strings.Contains(string(base), "Version: 1.2.0\n") {
+		t.Fatalf("recorded generation not updated:\n%s", base)
+	}
+}
+
+func TestWriteSpecChangelog(t *testing.T) {
+	dir := t.TempDir()
+	data := Data{
+		Project: inspect.Info{Name: "vlang", ImportPath: "example.com/vlang"},
+		Spec: SpecOptions{Version: "1.0.0", Packager: "Test <test@example.com>",
+			Date: time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	specPath := SpecPath(dir, "vlang")
+
+	if status, err := WriteSpec(dir, Templates{}, data, false); err != nil || status != FileCreated {
+		t.Fatalf("first WriteSpec(): got %q, %v", status, err)
+	}
+	base, _ := os.ReadFile(BasePath(specPath))
+	if !strings.HasSuffix(string(base), "\n%changelog\n") {
+		t.Fatalf("recorded generation has changelog entries:\n%s", base)
+	}
+
+	content, _ := os.ReadFile(specPath)
+	edited := string(content) + "- Ship the docs.\n"
+	if err := os.WriteFile(specPath, []byte(edited), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+
+	data.Spec.Date = time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+	if status, err := WriteSpec(dir, Templates{}, data, false); err != nil || status != FileKept {
+		t.Fatalf("WriteSpec() for the same version: got %q, %v; want %q", status, err, FileKept)
+	}
+
+	data.Spec.Version = "1.1.0"
+	if status, err := WriteSpec(dir, Templates{}, data, false); err != nil || status != FileUpdated {
+		t.Fatalf("WriteSpec() for a new version: got %q, %v; want %q", status, err, FileUpdated)
+	}
+	content, _ = os.ReadFile(specPath)
+	want := "%changelog\n" +
+		"* Wed Apr 01 2026 Test <test@example.com> 1.1.0-alt1\n- New version 1.1.0.\n\n" +
+		"* Thu Mar 05 2026 Test <test@example.com> 1.0.0-alt1\n- Initial build for ALT Sisyphus.\n- Ship the docs.\n"
+	if !strings.HasSuffix(string(content), want) || !strings.Contains(string(content), "Version: 1.1.0\n") {
+		t.Fatalf("spec after version bump:\n%s", content)
+	}
+
+	version, release, ok, err := RecordedVersion(specPath)
+	if err != nil || !ok || version != "1.1.0" || release != "alt1" {
+		t.Fatalf("RecordedVersion() = %q, %q, %t, %v", version, release, ok, err)
+	}
+}
+
+func TestPlanSpecUnrecorded(t *testing.T) {
+	dir := t.TempDir()
+	data := Data{
+		Project: inspect.Info{Name: "vlang", ImportPath: "example.com/vlang"},
+		Spec:    SpecOptions{Version: "1.0.0", Date: time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	specPath := SpecPath(dir, "vlang")
+
+	// A spec bootstrapped before generations were recorded.
+	if status, err := WriteSpec(dir, Templates{}, data, false); err != nil || status != FileCreated {
+		t.Fatalf("first WriteSpec(): got %q, %v", status, err)
+	}
+	if err := os.RemoveAll(filepath.Dir(BasePath(specPath))); err != nil {
+		t.Fatalf("remove recorded generation: %v", err)
+	}
+
+	out, err := PlanSpec(dir, Templates{}, data, false)
+	if err != nil || out.Status != FileKept || out.Unmanaged || out.Generated == nil {
+		t.Fatalf("PlanSpec() for an unedited spec: got %q, unmanaged %t, generated %t, %v",
+			out.Status, out.Unmanaged, out.Generated != nil, err)
+	}
+	if err := out.Write(); err != nil {
+		t.Fatalf("Write(): %v", err)
+	}
+	data.Spec.Version = "1.1.0"
+	if status, err := WriteSpec(dir, Templates{}, data, false); err != nil || status != FileUpdated {
+		t.Fatalf("WriteSpec() for a new version: got %q, %v; want %q", status, err, FileUpdated)
+	}
+
+	if err := os.RemoveAll(filepath.Dir(BasePath(specPath))); err != nil {
+		t.Fatalf("remove recorded generation: %v", err)
+	}
+	data.Spec.Version = "1.2.0"
+	out, err = PlanSpec(dir, Templates{}, data, false)
+	if err != nil || out.Status != FileKept || !out.Unmanaged || out.Generated != nil {
+		t.Fatalf("PlanSpec() for a new version: got %q, unmanaged %t, generated %t, %v",
+			out.Status, out.Unmanaged, out.Generated != nil, err)
+	}
+}
@@ -0,0 +1,149 @@
+package gear
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/reservation-v/vlang/internal/inspect"
+	"github.com/reservation-v/vlang/internal/license"
+	"github.com/reservation-v/vlang/internal/rpmver"
+)
+
+//go:embed templates/*.tmpl
+var builtinFS embed.FS
+
+// TemplateNames lists the generated artifacts that can be overridden by
+// <name>.tmpl in a template directory.
+var TemplateNames = []string{"rules", "spec"}
+
+// Data is what every template receives.
+type Data struct {
+	Project inspect.Info
+	Rules   RulesOptions
+	Spec    SpecOptions
+}
+
+// Helper functions available to templates:
+//
+//	rpmVersion "v1.2.3"   map a Go version to ALT Version/Release (.Version, .Release)
+//	licenses .Spec.Licenses  join license IDs for the License: tag ("Unknown" when empty)
+//	binaries .Spec.Binaries  %files lines for the binaries (%_bindir/* when none are known)
+//	buildPaths .Project.Binaries  main package directories for %golang_build ("." when none are known)
+//	changelogDate .Spec.Date date in %changelog header format
+//	join list sep            strings.Join
+var funcs = template.FuncMap{
+	"rpmVersion":    rpmver.FromGo,
+	"licenses":      licenseTag,
+	"binaries":      binaryFiles,
+	"buildPaths":    buildPaths,
+	"changelogDate": changelogDate,
+	"join":          strings.Join,
+}
+
+type Templates struct {
+	Dir string
+}
+
+// Render executes <Dir>/<name>.tmpl when it exists and the built-in
+// template otherwise.
+func (t Templates) Render(name string, data Data) ([]byte, error) {
+	text, err := t.source(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s template: %w", name, err)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("execute %s template: %w", name, err)
+	}
+
+	return b.Bytes(), nil
+}
+
+func (t Templates) source(name string) ([]byte, error) {
+	if t.Dir != "" {
+		path := filepath.Join(t.Dir, name+".tmpl")
+		text, err := os.ReadFile(path)
+		if err == nil {
+			return text, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+	}
+	return Builtin(name)
+}
+
+func Builtin(name string) ([]byte, error) {
+	text, err := builtinFS.ReadFile("templates/" + name + ".tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("unknown template %q", name)
+	}
+	return text, nil
+}
+
+// DumpTemplates writes the built-in templates into dir as a starting point
+// for overrides. Existing files are kept unless overwrite is set.
+func DumpTemplates(dir string, overwrite bool) (map[string]FileStatus, error) {
+	result := make(map[string]FileStatus, len(TemplateNames))
+	for _, name := range TemplateNames {
+		text, err := Builtin(name)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dir, name+".tmpl")
+		out, err := planUnlessExists(path, text, overwrite)
+		if err != nil {
+			return nil, err
+		}
+		if err := out.Write(); err != nil {
+			return nil, err
+		}
+		result[path] = out.Status
+	}
+	return result, nil
+}
+
+func licenseTag(ids []string) string {
+	if len(ids) == 0 {
+		return defaultLicense
+	}
+	return license.Expression(ids)
+}
+
+func binaryFiles(names []string) []string {
+	if len(names) == 0 {
+		return []string{"%_bindir/*"}
+	}
+	files := make([]string, 0, len(names))
+	for _, name := range names {
+		files = append(files, "%_bindir/"+name)
+	}
+	return files
+}
+
+func buildPaths(binaries []inspect.Binary) string {
+	if len(binaries) == 0 {
+		return "."
+	}
+	paths := make([]string, 0, len(binaries))
+	for _, bin := range binaries {
+		paths = append(paths, bin.RelPath)
+	}
+	return strings.Join(paths, " ")
+}
+
+func changelogDate(t time.Time) string {
+	return t.Format("Mon Jan 02 2006")
+}
@@ -0,0 +1,94 @@
+package gear
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reservation-v/vlang/internal/inspect"
+)
+
+func TestTemplatesOverride(t *testing.T) {
+	dir := t.TempDir()
+	custom := `{{.Project.Name}} {{with rpmVersion .Spec.Upstream}}{{.Version}}-{{.Release}}{{end}}
+License: {{licenses .Spec.Licenses}}
+{{range binaries .Spec.Binaries}}{{.}} {{end}}
+{{changelogDate .Spec.Date}}
+`
+	if err := os.WriteFile(filepath.Join(dir, "spec.tmpl"), []byte(custom), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	data := Data{
+		Project: inspect.Info{Name: "tool", ImportPath: "example.com/tool"},
+		Spec: SpecOptions{
+			Upstream: "v1.4.0-rc.2",
+			Licenses: []string{"MIT", "BSD-3-Clause"},
+			Binaries: []string{"tool", "toolctl"},
+			Date:     time.Date(2026, time.January, 9, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	got, err := Templates{Dir: dir}.Render("spec", data)
+	if err != nil {
+		t.Fatalf("Render(spec) error: %v", err)
+	}
+	want := "tool 1.4.0-alt0.rc2\nLicense: MIT and BSD-3-Clause\n%_bindir/tool %_bindir/toolctl \nFri Jan 09 2026\n"
+	if string(got) != want {
+		t.Fatalf("Render(spec):\ngot:\n%q\nwant:\n%q", got, want)
+	}
+
+	rules, err := Templates{Dir: dir}.Render("rules", data)
+	if err != nil {
+		t.Fatalf("Render(rules) error: %v", err)
+	}
+	if !strings.HasPrefix(string(rules), generatedMarker) {
+		t.Fatalf("rules without override should use the built-in template:\n%s", rules)
+	}
+}
+
+func TestTemplatesErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "rules.tmpl"), []byte("{{.Project.Nope}}"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "spec.tmpl"), []byte("{{if}}"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	for _, name := range []string{"rules", "spec", "unknown"} {
+		if _, err := (Templates{Dir: dir}).Render(name, Data{}); err == nil {
+			t.Errorf("Render(%s) want error, got nil", name)
+		}
+	}
+}
+
+func TestDumpTemplates(t *testing.T) {
+	dir := t.TempDir()
+
+	got, err := DumpTemplates(dir, false)
+	if err != nil {
+		t.Fatalf("DumpTemplates() error: %v", err)
+	}
+	for _, name := range TemplateNames {
+		path := filepath.Join(dir, name+".tmpl")
+		if got[path] != FileCreated {
+			t.Errorf("DumpTemplates() %s: got %q, want %q", name, got[path], FileCreated)
+		}
+		dumped, _ := os.ReadFile(path)
+		builtin, _ := Builtin(name)
+		if string(dumped) != string(builtin) {
+			t.Errorf("DumpTemplates() %s differs from built-in", name)
+		}
+	}
+
+	got, err = DumpTemplates(dir, false)
+	if err != nil {
+		t.Fatalf("second DumpTemplates() error: %v", err)
+	}
+	if status := got[filepath.Join(dir, "spec.tmpl")]; status != FileKept {
+		t.Fatalf("second DumpTemplates() spec: got %q, want %q", status, FileKept)
+	}
+}
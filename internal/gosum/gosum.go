@@ -0,0 +1,68 @@
+package gosum
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Line struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	GoMod   bool   `json:"go_mod"`
+	Hash    string `json:"hash"`
+	Line    int    `json:"line"`
+}
+
+type File struct {
+	Lines []Line
+}
+
+func Parse(data []byte) (*File, error) {
+	file := &File{}
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("go.sum line %d: malformed entry", i+1)
+		}
+
+		version, goMod := strings.CutSuffix(fields[1], "/go.mod")
+		if version == "" {
+			return nil, fmt.Errorf("go.sum line %d: empty version", i+1)
+		}
+		if !strings.Contains(fields[2], ":") {
+			return nil, fmt.Errorf("go.sum line %d: hash without algorithm prefix", i+1)
+		}
+
+		file.Lines = append(file.Lines, Line{
+			Path:    fields[0],
+			Version: version,
+			GoMod:   goMod,
+			Hash:    fields[2],
+			Line:    i + 1,
+		})
+	}
+
+	return file, nil
+}
+
+// Hash returns the h1 hash recorded for path@version, either for the module
+// tree or, when goMod is set, for its go.mod file.
+func (f *File) Hash(path, version string, goMod bool) string {
+	for _, line := range f.Lines {
+		if line.Path == path && line.Version == version && line.GoMod == goMod &&
+			strings.HasPrefix(line.Hash, "h1:") {
+			return line.Hash
+		}
+	}
+	return ""
+}
+
+func (f *File) Has(path, version string, goMod bool) bool {
+	return f.Hash(path, version, goMod) != ""
+}
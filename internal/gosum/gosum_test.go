@@ -0,0 +1,54 @@
+package gosum
+
+import "testing"
+
+const sample = `github.com/a/b v1.2.3 h1:zipHash=
+github.com/a/b v1.2.3/go.mod h1:modHash=
+
+golang.org/x/text v0.14.0/go.mod h1:textMod=
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if len(f.Lines) != 3 {
+		t.Fatalf("Lines: got %d, want 3", len(f.Lines))
+	}
+
+	want := Line{Path: "golang.org/x/text", Version: "v0.14.0", GoMod: true, Hash: "h1:textMod=", Line: 4}
+	if f.Lines[2] != want {
+		t.Errorf("Lines[2]: got %+v, want %+v", f.Lines[2], want)
+	}
+
+	if got := f.Hash("github.com/a/b", "v1.2.3", false); got != "h1:zipHash=" {
+		t.Errorf("Hash(zip): got %q", got)
+	}
+	if got := f.Hash("github.com/a/b", "v1.2.3", true); got != "h1:modHash=" {
+		t.Errorf("Hash(go.mod): got %q", got)
+	}
+	if f.Has("golang.org/x/text", "v0.14.0", false) {
+		t.Errorf("Has(text zip): got true, want false")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{name: "two_fields", in: "github.com/a/b v1.2.3\n"},
+		{name: "four_fields", in: "github.com/a/b v1.2.3 h1:x= extra\n"},
+		{name: "no_algorithm", in: "github.com/a/b v1.2.3 hash\n"},
+		{name: "empty_version", in: "github.com/a/b /go.mod h1:x=\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.in)); err == nil {
+				t.Fatalf("Parse() want error, got nil")
+			}
+		})
+	}
+}
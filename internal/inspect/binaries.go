@@ -0,0 +1,121 @@
+package inspect
+
+import (
+	"fmt"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type Binary struct {
+	Name       string `json:"name"`
+	ImportPath string `json:"import_path"`
+	RelPath    string `json:"rel_path"`
+}
+
+// targetContext decides which files build: ALT packages are built for
+// linux, on whatever architecture the host is.
+func targetContext() build.Context {
+	ctx := build.Default
+	ctx.GOOS = "linux"
+	ctx.CgoEnabled = true
+	return ctx
+}
+
+// Binaries finds the main packages of the module rooted at dir, skipping
+// nested modules and the directories the go command ignores. RelPath is
+// slash-separated and "." for the module root.
+func Binaries(dir, modulePath string) ([]Binary, error) {
+	ctx := targetContext()
+	binaries := []Binary{}
+
+	walkErr := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != dir {
+			if skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			if _, statErr := os.Stat(filepath.Join(p, "go.mod")); statErr == nil {
+				return filepath.SkipDir
+			}
+		}
+
+		isMain, mainErr := isMainPackage(ctx, p)
+		if mainErr != nil || !isMain {
+			return mainErr
+		}
+
+		rel, relErr := filepath.Rel(dir, p)
+		if relErr != nil {
+			return relErr
+		}
+		rel = filepath.ToSlash(rel)
+		importPath := path.Join(modulePath, rel)
+		binaries = append(binaries, Binary{
+			Name:       binaryName(importPath),
+			ImportPath: importPath,
+			RelPath:    rel,
+		})
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("find main packages: %w", walkErr)
+	}
+
+	sort.Slice(binaries, func(i, j int) bool { return binaries[i].ImportPath < binaries[j].ImportPath })
+	return binaries, nil
+}
+
+// isMainPackage reports whether the non-test Go files of dir that match
+// ctx declare package main.
+func isMainPackage(ctx build.Context, dir string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		match, matchErr := ctx.MatchFile(dir, name)
+		if matchErr != nil {
+			return false, fmt.Errorf("match %s: %w", filepath.Join(dir, name), matchErr)
+		}
+		if !match {
+			continue
+		}
+
+		file, parseErr := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.PackageClauseOnly)
+		if parseErr != nil {
+			return false, fmt.Errorf("parse %s: %w", filepath.Join(dir, name), parseErr)
+		}
+		// The files of a package agree on its name; the first one decides.
+		return file.Name.Name == "main", nil
+	}
+	return false, nil
+}
+
+// binaryName is the file name go install gives the main package
+// importPath: its last element, or the one before a major version suffix.
+func binaryName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && isMajorVersionSegment(name) {
+		name = elems[len(elems)-2]
+	}
+	return name
+}
@@ -0,0 +1,68 @@
+package inspect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBinaries(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                      "module example.com/tool/v2\n\ngo 1.22\n",
+		"main.go":                     "package main\n\nfunc main() {}\n",
+		"cmd/serve/main.go":           "// Server entry point.\npackage main\n\nfunc main() {}\n",
+		"cmd/serve/main_test.go":      "package main\n",
+		"cmd/winonly/main_windows.go": "package main\n\nfunc main() {}\n",
+		"cmd/gen/gen.go":              "//go:build ignore\n\npackage main\n\nfunc main() {}\n",
+		"cmd/gen/doc.go":              "package gen\n",
+		"cmd/linux/main.go":           "//go:build linux\n\npackage main\n\nfunc main() {}\n",
+		"internal/lib/lib.go":         "package lib\n",
+		"testdata/demo/main.go":       "package main\n",
+		"_examples/demo/main.go":      "package main\n",
+		"nested/go.mod":               "module example.com/nested\n",
+		"nested/main.go":              "package main\n",
+		"onlytests/x_test.go":         "package main\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	got, err := Binaries(root, "example.com/tool/v2")
+	if err != nil {
+		t.Fatalf("Binaries() error: %v", err)
+	}
+
+	want := []Binary{
+		{Name: "tool", ImportPath: "example.com/tool/v2", RelPath: "."},
+		{Name: "linux", ImportPath: "example.com/tool/v2/cmd/linux", RelPath: "cmd/linux"},
+		{Name: "serve", ImportPath: "example.com/tool/v2/cmd/serve", RelPath: "cmd/serve"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Binaries():\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestBinaryName(t *testing.T) {
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{importPath: "example.com/tool", want: "tool"},
+		{importPath: "example.com/tool/v2", want: "tool"},
+		{importPath: "example.com/tool/cmd/v2ctl", want: "v2ctl"},
+		{importPath: "tool", want: "tool"},
+	}
+	for _, tt := range tests {
+		if got := binaryName(tt.importPath); got != tt.want {
+			t.Errorf("binaryName(%q) = %q, want %q", tt.importPath, got, tt.want)
+		}
+	}
+}
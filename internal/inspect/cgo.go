@@ -0,0 +1,261 @@
+package inspect
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CgoPackage is a package importing "C" and the C libraries its #cgo
+// directives link against.
+type CgoPackage struct {
+	ImportPath string   `json:"import_path"`
+	PkgConfig  []string `json:"pkg_config,omitempty"`
+	Libs       []string `json:"libs,omitempty"`
+}
+
+// systemLibs come with glibc and need no BuildRequires of their own.
+var systemLibs = []string{"c", "m", "pthread", "dl", "rt", "util", "resolv"}
+
+// ScanCgo finds the packages under root that use cgo for the target
+// platform. Import paths are importPrefix joined with the directory.
+// With skipModules, nested modules and vendor/ are left out as for the
+// module itself; without it every directory is scanned, as in vendor/.
+func ScanCgo(root, importPrefix string, skipModules bool) ([]CgoPackage, error) {
+	ctx := targetContext()
+	packages := []CgoPackage{}
+
+	walkErr := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != root && skipModules {
+			if skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			if _, statErr := os.Stat(filepath.Join(p, "go.mod")); statErr == nil {
+				return filepath.SkipDir
+			}
+		}
+
+		pkg, ok, scanErr := scanCgoDir(ctx, p)
+		if scanErr != nil || !ok {
+			return scanErr
+		}
+		rel, relErr := filepath.Rel(root, p)
+		if relErr != nil {
+			return relErr
+		}
+		pkg.ImportPath = path.Join(importPrefix, filepath.ToSlash(rel))
+		packages = append(packages, pkg)
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("scan cgo: %w", walkErr)
+	}
+
+	sort.Slice(packages, func(i, j int) bool { return packages[i].ImportPath < packages[j].ImportPath })
+	return packages, nil
+}
+
+func scanCgoDir(ctx build.Context, dir string) (CgoPackage, bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return CgoPackage{}, false, err
+	}
+
+	var pkg CgoPackage
+	usesCgo := false
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		match, matchErr := ctx.MatchFile(dir, name)
+		if matchErr != nil {
+			return CgoPackage{}, false, fmt.Errorf("match %s: %w", filepath.Join(dir, name), matchErr)
+		}
+		if !match {
+			continue
+		}
+
+		file, parseErr := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ImportsOnly|parser.ParseComments)
+		if parseErr != nil {
+			return CgoPackage{}, false, fmt.Errorf("parse %s: %w", filepath.Join(dir, name), parseErr)
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.IMPORT {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				imp := spec.(*ast.ImportSpec)
+				if imp.Path.Value != strconv.Quote("C") {
+					continue
+				}
+				usesCgo = true
+				// The preamble is the comment on the import spec, or on the
+				// declaration when it has no parentheses.
+				doc := imp.Doc
+				if doc == nil && !gen.Lparen.IsValid() {
+					doc = gen.Doc
+				}
+				if doc != nil {
+					parseCgoDirectives(ctx, doc.Text(), &pkg)
+				}
+			}
+		}
+	}
+
+	slices.Sort(pkg.PkgConfig)
+	pkg.PkgConfig = slices.Compact(pkg.PkgConfig)
+	slices.Sort(pkg.Libs)
+	pkg.Libs = slices.Compact(pkg.Libs)
+	return pkg, usesCgo, nil
+}
+
+// parseCgoDirectives collects pkg-config packages and -l libraries from
+// the #cgo lines of preamble that apply to ctx.
+func parseCgoDirectives(ctx build.Context, preamble string, pkg *CgoPackage) {
+	for _, line := range strings.Split(preamble, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "#cgo")
+		if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		head, args, ok := strings.Cut(rest, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(head)
+		if len(fields) == 0 || !cgoConstraintMatches(ctx, fields[:len(fields)-1]) {
+			continue
+		}
+
+		switch fields[len(fields)-1] {
+		case "pkg-config":
+			for _, arg := range strings.Fields(args) {
+				if !strings.HasPrefix(arg, "-") {
+					pkg.PkgConfig = append(pkg.PkgConfig, arg)
+				}
+			}
+		case "LDFLAGS":
+			ldflags := strings.Fields(args)
+			if bundlesLibs(ldflags) {
+				continue
+			}
+			for _, arg := range ldflags {
+				lib, ok := strings.CutPrefix(arg, "-l")
+				if ok && lib != "" && !slices.Contains(systemLibs, lib) {
+					pkg.Libs = append(pkg.Libs, lib)
+				}
+			}
+		}
+	}
+}
+
+// bundlesLibs reports whether ldflags search a directory of the package
+// itself: the -l libraries of such a line ship with the module and need
+// no BuildRequires.
+func bundlesLibs(ldflags []string) bool {
+	for i, arg := range ldflags {
+		dir, ok := strings.CutPrefix(arg, "-L")
+		if ok && dir == "" && i+1 < len(ldflags) {
+			dir = ldflags[i+1]
+		}
+		if ok && strings.HasPrefix(dir, "${SRCDIR}") {
+			return true
+		}
+	}
+	return false
+}
+
+// cgoConstraintMatches evaluates the build options of a #cgo line: any of
+// them may match, and each is a comma-separated list of terms that all must.
+func cgoConstraintMatches(ctx build.Context, options []string) bool {
+	if len(options) == 0 {
+		return true
+	}
+	for _, option := range options {
+		all := true
+		for _, term := range strings.Split(option, ",") {
+			name, negated := strings.CutPrefix(term, "!")
+			ok := name == ctx.GOOS || name == ctx.GOARCH || name == "unix" || name == "cgo" || name == "gc"
+			if ok == negated {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// AddVendorCgo adds the cgo packages of the vendor directory info was
+// read from to info.Cgo and recomputes info.BuildRequires.
+func AddVendorCgo(info *Info) error {
+	vendor := info.vendor
+	if vendor == "" {
+		vendor = filepath.Join(info.Dir, "vendor")
+	}
+	vendored, err := ScanCgo(vendor, "", false)
+	if err != nil {
+		return err
+	}
+	info.Cgo = append(info.Cgo, vendored...)
+	info.BuildRequires = CgoBuildRequires(info.Cgo)
+	return nil
+}
+
+// CgoBuildRequires turns the C libraries of packages into candidate
+// BuildRequires: pkgconfig(name) for pkg-config packages and
+// lib<name>-devel for linked libraries.
+func CgoBuildRequires(packages []CgoPackage) []string {
+	requires := []string{}
+	for _, pkg := range packages {
+		for _, name := range pkg.PkgConfig {
+			requires = append(requires, "pkgconfig("+name+")")
+		}
+		for _, lib := range pkg.Libs {
+			requires = append(requires, LibDevel(lib))
+		}
+	}
+	slices.Sort(requires)
+	return slices.Compact(requires)
+}
+
+// develPackages names the ALT header packages that do not follow the
+// lib<name>-devel pattern.
+var develPackages = map[string]string{
+	"z":   "zlib-devel",
+	"bz2": "bzlib-devel",
+}
+
+// LibDevel is the usual ALT package name with the headers of library lib.
+func LibDevel(lib string) string {
+	if name, ok := develPackages[lib]; ok {
+		return name
+	}
+	if strings.HasPrefix(lib, "lib") {
+		return lib + "-devel"
+	}
+	return "lib" + lib + "-devel"
+}
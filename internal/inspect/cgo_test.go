@@ -0,0 +1,54 @@
+package inspect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScanCgo(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n",
+		"db/db.go": "package db\n\n" +
+			"/*\n#cgo pkg-config: --static sqlite3 zlib\n#cgo linux LDFLAGS: -lssl -lm\n#cgo windows LDFLAGS: -lws2_32\n" +
+			"#cgo !darwin,cgo LDFLAGS: -lcrypto\n#include <sqlite3.h>\n*/\nimport \"C\"\n",
+		"db/other.go":        "package db\n\n// #cgo LDFLAGS: -lz\nimport \"C\"\n",
+		"db/db_test.go":      "package db\n\n// #cgo LDFLAGS: -ltestonly\nimport \"C\"\n",
+		"win/win_windows.go": "package win\n\n// #cgo LDFLAGS: -lgdi32\nimport \"C\"\n",
+		"grouped/g.go":       "package grouped\n\nimport (\n\t\"fmt\"\n\n\t// #cgo pkg-config: libusb-1.0\n\t\"C\"\n)\n\nvar _ = fmt.Sprint\n",
+		"pure/pure.go":       "package pure\n",
+		"bundled/b.go":       "package bundled\n\n// #cgo LDFLAGS: -L${SRCDIR}/lib -lbundledfoo\n// #cgo linux LDFLAGS: -L ${SRCDIR}/linux -lbundledbar\nimport \"C\"\n",
+		"nested/go.mod":      "module example.com/nested\n",
+		"nested/n.go":        "package nested\n\n// #cgo LDFLAGS: -lnested\nimport \"C\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	got, err := ScanCgo(root, "example.com/app", true)
+	if err != nil {
+		t.Fatalf("ScanCgo() error: %v", err)
+	}
+	want := []CgoPackage{
+		{ImportPath: "example.com/app/bundled"},
+		{ImportPath: "example.com/app/db", PkgConfig: []string{"sqlite3", "zlib"}, Libs: []string{"crypto", "ssl", "z"}},
+		{ImportPath: "example.com/app/grouped", PkgConfig: []string{"libusb-1.0"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ScanCgo():\ngot  %+v\nwant %+v", got, want)
+	}
+
+	requires := CgoBuildRequires(got)
+	wantRequires := []string{"libcrypto-devel", "libssl-devel", "pkgconfig(libusb-1.0)", "pkgconfig(sqlite3)", "pkgconfig(zlib)", "zlib-devel"}
+	if !reflect.DeepEqual(requires, wantRequires) {
+		t.Fatalf("CgoBuildRequires():\ngot  %q\nwant %q", requires, wantRequires)
+	}
+}
@@ -0,0 +1,260 @@
+package inspect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reservation-v/vlang/internal/semver"
+)
+
+// Git describes the git checkout a module lives in. Dirty and Untracked
+// are relative to Root and limited to the module directory.
+type Git struct {
+	Root      string   `json:"root"`
+	Branch    string   `json:"branch"`
+	Head      string   `json:"head"`
+	Dirty     []string `json:"dirty"`
+	Untracked []string `json:"untracked"`
+
+	// Tag is the highest semver tag reachable from HEAD, with the module
+	// subdirectory prefix of a nested module stripped.
+	Tag             string `json:"tag"`
+	CommitsSinceTag int    `json:"commits_since_tag"`
+
+	VendorTracked bool `json:"vendor_tracked"`
+	GearTracked   bool `json:"gear_tracked"`
+
+	// Version is Tag when HEAD is tagged and a pseudo-version of HEAD
+	// otherwise, as the go command would give it.
+	Version string `json:"version"`
+}
+
+// gitEnv keeps user and system configuration, locale and GIT_*
+// variables of the caller from changing what git prints.
+func gitEnv() []string {
+	return []string{
+		"PATH=" + os.Getenv("PATH"),
+		"LC_ALL=C",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_CONFIG_GLOBAL=" + os.DevNull,
+		"GIT_OPTIONAL_LOCKS=0",
+		"GIT_TERMINAL_PROMPT=0",
+	}
+}
+
+// errGitExit is a git command that ran and exited non-zero; errNotRepo
+// is one that did so because dir is outside any work tree.
+var (
+	errGitExit = errors.New("git exited non-zero")
+	errNotRepo = errors.New("not a git repository")
+)
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = gitEnv()
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			msg := strings.TrimSpace(stderr.String())
+			if strings.Contains(msg, "not a git repository") {
+				return "", fmt.Errorf("git %s: %w: %w: %s", args[0], errGitExit, errNotRepo, msg)
+			}
+			return "", fmt.Errorf("git %s: %w: %s", args[0], errGitExit, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// InspectGit reads the git facts of the module modulePath in dir. It
+// returns nil when dir is not inside a work tree or git is not installed.
+// Any other failure, such as git refusing a repository owned by another
+// user, is an error.
+func InspectGit(dir, modulePath string) (*Git, error) {
+	root, err := gitOutput(dir, "rev-parse", "--show-toplevel")
+	if errors.Is(err, errNotRepo) || errors.Is(err, exec.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	g := &Git{Root: strings.TrimSpace(root), Dirty: []string{}, Untracked: []string{}}
+
+	branch, err := gitOutput(dir, "symbolic-ref", "-q", "--short", "HEAD")
+	if err != nil && !errors.Is(err, errGitExit) {
+		return nil, err
+	}
+	g.Branch = strings.TrimSpace(branch)
+
+	if err := g.readStatus(dir); err != nil {
+		return nil, err
+	}
+	if g.VendorTracked, err = gitTracked(dir, "vendor"); err != nil {
+		return nil, err
+	}
+	if g.GearTracked, err = gitTracked(dir, ".gear"); err != nil {
+		return nil, err
+	}
+
+	head, err := gitOutput(dir, "rev-parse", "-q", "--verify", "HEAD^{commit}")
+	if errors.Is(err, errGitExit) {
+		// No commits yet.
+		return g, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	g.Head = strings.TrimSpace(head)
+
+	prefix, err := tagPrefix(dir, g.Root)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := gitOutput(dir, "tag", "--merged", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	var fullTag string
+	for _, tag := range strings.Fields(tags) {
+		v, ok := strings.CutPrefix(tag, prefix)
+		if !ok || !isReleaseTag(v) || !matchesPathMajor(v, modulePath) {
+			continue
+		}
+		if g.Tag == "" || semver.Compare(v, g.Tag) > 0 {
+			g.Tag, fullTag = v, tag
+		}
+	}
+	if fullTag != "" {
+		count, err := gitOutput(dir, "rev-list", "--count", "refs/tags/"+fullTag+"..HEAD")
+		if err != nil {
+			return nil, err
+		}
+		if g.CommitsSinceTag, err = strconv.Atoi(strings.TrimSpace(count)); err != nil {
+			return nil, fmt.Errorf("git rev-list: %w", err)
+		}
+	}
+
+	if fullTag != "" && g.CommitsSinceTag == 0 {
+		g.Version = g.Tag
+		return g, nil
+	}
+	stamp, err := gitOutput(dir, "show", "-s", "--format=%ct", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(stamp), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("git show: commit time: %w", err)
+	}
+	g.Version = pseudoVersion(g.Tag, modulePath, time.Unix(seconds, 0), g.Head)
+	return g, nil
+}
+
+// readStatus fills Dirty and Untracked from git status of dir.
+func (g *Git) readStatus(dir string) error {
+	status, err := gitOutput(dir, "status", "--porcelain", "-z", "--untracked-files=all", "--", ".")
+	if err != nil {
+		return err
+	}
+	entries := strings.Split(status, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		code, name := entry[:2], entry[3:]
+		if code == "??" {
+			g.Untracked = append(g.Untracked, name)
+			continue
+		}
+		g.Dirty = append(g.Dirty, name)
+		// Renames and copies are followed by the original path.
+		if code[0] == 'R' || code[0] == 'C' {
+			i++
+		}
+	}
+	return nil
+}
+
+func gitTracked(dir, name string) (bool, error) {
+	out, err := gitOutput(dir, "ls-files", "--", name)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+// tagPrefix is the tag prefix of a module in a subdirectory of the
+// repository: tags of example.com/repo/sub are named sub/vX.Y.Z.
+func tagPrefix(dir, root string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", dir, err)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", dir, err)
+	}
+	if rel == "." {
+		return "", nil
+	}
+	return filepath.ToSlash(rel) + "/", nil
+}
+
+func isReleaseTag(tag string) bool {
+	v, err := semver.Parse(tag)
+	return err == nil && !v.Short && v.Build == ""
+}
+
+// matchesPathMajor reports whether tag has the major version modulePath
+// allows, as the go command checks it: the one of a /vN or gopkg.in .vN
+// suffix, or v0 and v1 without one.
+func matchesPathMajor(tag, modulePath string) bool {
+	major := semver.Major(tag)
+	last := path.Base(modulePath)
+	if strings.HasPrefix(modulePath, "gopkg.in/") {
+		if i := strings.LastIndex(last, ".v"); i > 0 && isMajorVersionSegment(last[i+1:]) {
+			return major == last[i+1:]
+		}
+	}
+	if last != modulePath && isMajorVersionSegment(last) {
+		return major == last
+	}
+	return major == "v0" || major == "v1"
+}
+
+// pseudoVersion builds the go command's pseudo-version for revision made
+// at t on top of tag, or with no tag at all.
+func pseudoVersion(tag, modulePath string, t time.Time, revision string) string {
+	stamp := t.UTC().Format("20060102150405")
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if tag == "" {
+		major := "v0"
+		if last := path.Base(modulePath); isMajorVersionSegment(last) {
+			major = last
+		}
+		return major + ".0.0-" + stamp + "-" + revision
+	}
+
+	v, _ := semver.Parse(tag)
+	if v.Prerelease != "" {
+		return tag + ".0." + stamp + "-" + revision
+	}
+	patch, _ := strconv.ParseUint(v.Patch, 10, 64)
+	return fmt.Sprintf("v%s.%s.%d-0.%s-%s", v.Major, v.Minor, patch+1, stamp, revision)
+}
@@ -0,0 +1,255 @@
+package inspect
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reservation-v/vlang/internal/semver"
+)
+
+// commitTime is the author and committer date of every test commit.
+const commitTime = "2024-03-05T06:07:08Z"
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(gitEnv(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_AUTHOR_DATE="+commitTime,
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com", "GIT_COMMITTER_DATE="+commitTime,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeRepoFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func newRepo(t *testing.T) string {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("resolve temp dir: %v", err)
+	}
+	runGit(t, dir, "init", "-q", "-b", "main")
+	return dir
+}
+
+func commitAll(t *testing.T, dir, message string) string {
+	t.Helper()
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", message)
+	return runGit(t, dir, "rev-parse", "HEAD")
+}
+
+func TestInspectGit(t *testing.T) {
+	dir := newRepo(t)
+	writeRepoFile(t, dir, "go.mod", "module example.com/tool\n")
+	writeRepoFile(t, dir, "main.go", "package main\n")
+	writeRepoFile(t, dir, "vendor/modules.txt", "")
+	commitAll(t, dir, "initial")
+	runGit(t, dir, "tag", "v1.0.0")
+	runGit(t, dir, "tag", "v1.1.0-rc.1")
+	runGit(t, dir, "tag", "release-2")
+
+	writeRepoFile(t, dir, "old.go", "package main\n")
+	commitAll(t, dir, "second")
+	runGit(t, dir, "mv", "old.go", "new.go")
+	head := commitAll(t, dir, "third")
+
+	// A newer tag that HEAD cannot reach.
+	runGit(t, dir, "checkout", "-q", "-b", "side", "HEAD~2")
+	writeRepoFile(t, dir, "side.go", "package main\n")
+	commitAll(t, dir, "side")
+	runGit(t, dir, "tag", "v2.0.0")
+	runGit(t, dir, "checkout", "-q", "main")
+
+	writeRepoFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+	runGit(t, dir, "mv", "new.go", "renamed.go")
+	writeRepoFile(t, dir, "notes/todo.txt", "later\n")
+
+	got, err := InspectGit(dir, "example.com/tool")
+	if err != nil {
+		t.Fatalf("InspectGit() error: %v", err)
+	}
+	want := &Git{
+		Root:            dir,
+		Branch:          "main",
+		Head:            head,
+		Dirty:           []string{"main.go", "renamed.go"},
+		Untracked:       []string{"notes/todo.txt"},
+		Tag:             "v1.1.0-rc.1",
+		CommitsSinceTag: 2,
+		VendorTracked:   true,
+		GearTracked:     false,
+		Version:         "v1.1.0-rc.1.0.20240305060708-" + head[:12],
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("InspectGit():\ngot  %+v\nwant %+v", got, want)
+	}
+	if !semver.IsPseudo(got.Version) {
+		t.Errorf("Version %s is not a pseudo-version", got.Version)
+	}
+}
+
+func TestInspectGitTaggedDetached(t *testing.T) {
+	dir := newRepo(t)
+	writeRepoFile(t, dir, ".gear/rules", "tar: .\n")
+	commitAll(t, dir, "initial")
+	runGit(t, dir, "tag", "v0.3.0")
+	runGit(t, dir, "checkout", "-q", "--detach")
+
+	got, err := InspectGit(dir, "example.com/tool")
+	if err != nil {
+		t.Fatalf("InspectGit() error: %v", err)
+	}
+	if got.Branch != "" || got.Tag != "v0.3.0" || got.CommitsSinceTag != 0 || got.Version != "v0.3.0" {
+		t.Errorf("InspectGit(): got %+v", got)
+	}
+	if !got.GearTracked || got.VendorTracked {
+		t.Errorf("tracked: got vendor %t, .gear %t", got.VendorTracked, got.GearTracked)
+	}
+}
+
+func TestInspectGitNestedModule(t *testing.T) {
+	dir := newRepo(t)
+	writeRepoFile(t, dir, "go.mod", "module example.com/repo\n")
+	writeRepoFile(t, dir, "sub/go.mod", "module example.com/repo/sub/v2\n")
+	head := commitAll(t, dir, "initial")
+	runGit(t, dir, "tag", "v9.0.0")
+	runGit(t, dir, "tag", "sub/v2.1.0")
+	writeRepoFile(t, dir, "README", "root only\n")
+
+	got, err := InspectGit(filepath.Join(dir, "sub"), "example.com/repo/sub/v2")
+	if err != nil {
+		t.Fatalf("InspectGit() error: %v", err)
+	}
+	if got.Head != head || got.Tag != "v2.1.0" || got.Version != "v2.1.0" {
+		t.Errorf("InspectGit(): got %+v", got)
+	}
+	if len(got.Untracked) != 0 {
+		t.Errorf("Untracked outside the module: %v", got.Untracked)
+	}
+}
+
+func TestInspectGitMajorVersion(t *testing.T) {
+	dir := newRepo(t)
+	writeRepoFile(t, dir, "go.mod", "module example.com/tool/v2\n")
+	commitAll(t, dir, "initial")
+	runGit(t, dir, "tag", "v1.5.0")
+	writeRepoFile(t, dir, "a.go", "package tool\n")
+	commitAll(t, dir, "second")
+	runGit(t, dir, "tag", "v2.1.0")
+	writeRepoFile(t, dir, "b.go", "package tool\n")
+	commitAll(t, dir, "third")
+	runGit(t, dir, "tag", "v3.0.0")
+
+	tests := []struct {
+		modulePath string
+		tag        string
+		commits    int
+		major      string
+	}{
+		{modulePath: "example.com/tool/v2", tag: "v2.1.0", commits: 1, major: "v2"},
+		{modulePath: "example.com/tool", tag: "v1.5.0", commits: 2, major: "v1"},
+		{modulePath: "example.com/tool/v4", tag: "", commits: 0, major: "v4"},
+	}
+	for _, tt := range tests {
+		got, err := InspectGit(dir, tt.modulePath)
+		if err != nil {
+			t.Fatalf("InspectGit(%s) error: %v", tt.modulePath, err)
+		}
+		if got.Tag != tt.tag || got.CommitsSinceTag != tt.commits || semver.Major(got.Version) != tt.major {
+			t.Errorf("InspectGit(%s): got tag %q, %d commits, version %s", tt.modulePath, got.Tag, got.CommitsSinceTag, got.Version)
+		}
+	}
+}
+
+func TestMatchesPathMajor(t *testing.T) {
+	tests := []struct {
+		tag, modulePath string
+		want            bool
+	}{
+		{"v1.2.0", "example.com/tool", true},
+		{"v0.1.0", "example.com/tool", true},
+		{"v2.0.0", "example.com/tool", false},
+		{"v2.0.0", "example.com/tool/v2", true},
+		{"v1.9.0", "example.com/tool/v2", false},
+		{"v3.0.0", "gopkg.in/yaml.v3", true},
+		{"v2.4.0", "gopkg.in/yaml.v3", false},
+	}
+	for _, tt := range tests {
+		if got := matchesPathMajor(tt.tag, tt.modulePath); got != tt.want {
+			t.Errorf("matchesPathMajor(%q, %q) = %t, want %t", tt.tag, tt.modulePath, got, tt.want)
+		}
+	}
+}
+
+func TestInspectGitNoCommitsOrRepo(t *testing.T) {
+	dir := newRepo(t)
+	writeRepoFile(t, dir, "go.mod", "module example.com/tool\n")
+
+	got, err := InspectGit(dir, "example.com/tool")
+	if err != nil {
+		t.Fatalf("InspectGit() error: %v", err)
+	}
+	want := &Git{Root: dir, Branch: "main", Dirty: []string{}, Untracked: []string{"go.mod"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("InspectGit():\ngot  %+v\nwant %+v", got, want)
+	}
+
+	if got, err := InspectGit(t.TempDir(), "example.com/tool"); got != nil || err != nil {
+		t.Fatalf("InspectGit() outside a repository = %+v, %v", got, err)
+	}
+}
+
+func TestInspectGitErrors(t *testing.T) {
+	dir := newRepo(t)
+	writeRepoFile(t, dir, ".git/config", "[core\n")
+	if got, err := InspectGit(dir, "example.com/tool"); err == nil {
+		t.Fatalf("InspectGit() with a broken config = %+v, want error", got)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if got, err := InspectGit(dir, "example.com/tool"); got != nil || err != nil {
+		t.Fatalf("InspectGit() without git = %+v, %v", got, err)
+	}
+}
+
+func TestPseudoVersion(t *testing.T) {
+	at := time.Date(2024, time.March, 5, 6, 7, 8, 0, time.UTC)
+	rev := "0123456789abcdef"
+	tests := []struct {
+		tag, modulePath, want string
+	}{
+		{tag: "", modulePath: "example.com/tool", want: "v0.0.0-20240305060708-0123456789ab"},
+		{tag: "", modulePath: "example.com/tool/v3", want: "v3.0.0-20240305060708-0123456789ab"},
+		{tag: "v1.2.3", modulePath: "example.com/tool", want: "v1.2.4-0.20240305060708-0123456789ab"},
+		{tag: "v1.3.0-beta.2", modulePath: "example.com/tool", want: "v1.3.0-beta.2.0.20240305060708-0123456789ab"},
+	}
+	for _, tt := range tests {
+		got := pseudoVersion(tt.tag, tt.modulePath, at, rev)
+		if got != tt.want {
+			t.Errorf("pseudoVersion(%q, %q) = %q, want %q", tt.tag, tt.modulePath, got, tt.want)
+		}
+		if !semver.IsPseudo(got) {
+			t.Errorf("pseudoVersion(%q, %q) = %q is not a pseudo-version", tt.tag, tt.modulePath, got)
+		}
+	}
+}
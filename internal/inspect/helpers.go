@@ -25,6 +25,12 @@ func NameFromModulePath(modulePath string) (string, error) {
 		return "", fmt.Errorf("invalid module path: %q", modulePath)
 	}
 
+	if moduleSlice[0] == "gopkg.in" {
+		if idx := strings.LastIndex(name, ".v"); idx > 0 && isMajorVersionSegment(name[idx+1:]) {
+			name = name[:idx]
+		}
+	}
+
 	name = strings.ToLower(name)
 	return name, nil
 }
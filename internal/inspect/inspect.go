@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/reservation-v/vlang/internal/license"
 	"github.com/reservation-v/vlang/internal/modfile"
 )
 
@@ -18,14 +19,51 @@ type Info struct {
 	HasGearDir   bool   `json:"has_gear_dir"`
 	HasGearRules bool   `json:"has_gear_rules"`
 	HasGearSpec  bool   `json:"has_gear_spec"`
+
+	Workspace *Workspace `json:"workspace"`
+	Modules   []Module   `json:"modules"`
+	Binaries  []Binary   `json:"binaries"`
+
+	// Cgo lists the packages using cgo; BuildRequires are the candidate
+	// spec BuildRequires for the C libraries they need.
+	Cgo           []CgoPackage `json:"cgo"`
+	BuildRequires []string     `json:"build_requires"`
+
+	Licenses license.Report `json:"licenses"`
+	Provides []Provide      `json:"provides"`
+
+	// Git is nil outside a git work tree.
+	Git *Git `json:"git"`
+
+	// vendor is the vendor directory the facts were read from.
+	vendor string
+}
+
+// Staged stands in for the go.mod and vendor/ of the module directory
+// with ones prepared elsewhere, as a bootstrap dry run stages them.
+type Staged struct {
+	GoMod  []byte // nil reads dir/go.mod
+	Vendor string // "" reads dir/vendor
 }
 
 func Inspect(dir string) (Info, error) {
-	goModPath := filepath.Join(dir, "go.mod")
+	return InspectStaged(dir, Staged{})
+}
 
-	file, readErr := os.ReadFile(goModPath)
-	if readErr != nil {
-		return Info{}, fmt.Errorf("read go.mod: %w", readErr)
+// InspectStaged inspects dir as if its go.mod and vendor/ were those of
+// staged.
+func InspectStaged(dir string, staged Staged) (Info, error) {
+	file := staged.GoMod
+	if file == nil {
+		var readErr error
+		file, readErr = os.ReadFile(filepath.Join(dir, "go.mod"))
+		if readErr != nil {
+			return Info{}, fmt.Errorf("read go.mod: %w", readErr)
+		}
+	}
+	vendor := staged.Vendor
+	if vendor == "" {
+		vendor = filepath.Join(dir, "vendor")
 	}
 
 	modulePath, parseModErr := modfile.ParseModulePath(file)
@@ -44,7 +82,7 @@ func Inspect(dir string) (Info, error) {
 		return Info{}, fmt.Errorf("parse go version: %w", goParseErr)
 	}
 
-	hasVendor, hasVendorErr := hasDir(dir, "vendor")
+	hasVendor, hasVendorErr := hasDir(vendor, ".")
 	if hasVendorErr != nil {
 		return Info{}, hasVendorErr
 	}
@@ -65,15 +103,59 @@ func Inspect(dir string) (Info, error) {
 		return Info{}, hasSpecErr
 	}
 
+	workspace, workspaceErr := LoadWorkspace(dir)
+	if workspaceErr != nil {
+		return Info{}, workspaceErr
+	}
+
+	modules, modulesErr := DiscoverModules(dir)
+	if modulesErr != nil {
+		return Info{}, modulesErr
+	}
+
+	binaries, binariesErr := Binaries(dir, modulePath)
+	if binariesErr != nil {
+		return Info{}, binariesErr
+	}
+
+	cgo, cgoErr := ScanCgo(dir, modulePath, true)
+	if cgoErr != nil {
+		return Info{}, cgoErr
+	}
+
+	licenses, licensesErr := license.DetectVendor(dir, vendor)
+	if licensesErr != nil {
+		return Info{}, licensesErr
+	}
+
+	provides, providesErr := vendorProvides(vendor)
+	if providesErr != nil {
+		return Info{}, providesErr
+	}
+
+	git, gitErr := InspectGit(dir, modulePath)
+	if gitErr != nil {
+		return Info{}, gitErr
+	}
+
 	return Info{
-		Dir:          dir,
-		ModulePath:   modulePath,
-		ImportPath:   importPath,
-		Name:         name,
-		GoVersion:    goVersion,
-		HasVendor:    hasVendor,
-		HasGearDir:   hasGearDir,
-		HasGearRules: hasGearRules,
-		HasGearSpec:  hasGearSpec,
+		Dir:           dir,
+		ModulePath:    modulePath,
+		ImportPath:    importPath,
+		Name:          name,
+		GoVersion:     goVersion,
+		HasVendor:     hasVendor,
+		HasGearDir:    hasGearDir,
+		HasGearRules:  hasGearRules,
+		HasGearSpec:   hasGearSpec,
+		Workspace:     workspace,
+		Modules:       modules,
+		Binaries:      binaries,
+		Cgo:           cgo,
+		BuildRequires: CgoBuildRequires(cgo),
+		Licenses:      licenses,
+		Provides:      provides,
+		Git:           git,
+		vendor:        vendor,
 	}, nil
 }
@@ -0,0 +1,131 @@
+package inspect
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/reservation-v/vlang/internal/modfile"
+)
+
+type Module struct {
+	RelPath     string   `json:"rel_path"`
+	ModulePath  string   `json:"module_path"`
+	GoVersion   string   `json:"go_version,omitempty"`
+	SiblingDeps []string `json:"sibling_deps,omitempty"`
+
+	// Error is why a go.mod could not be read; the other fields but
+	// RelPath are then empty.
+	Error string `json:"error,omitempty"`
+}
+
+// DiscoverModules finds every go.mod under root, skipping the directories the
+// go command ignores (vendor, testdata, and names starting with '.' or '_').
+// RelPath is slash-separated and "." for the root module. A go.mod that
+// cannot be read or parsed is listed with its Error rather than failing the
+// whole discovery.
+func DiscoverModules(root string) ([]Module, error) {
+	var modules []Module
+	replaces := make(map[string][]string)
+
+	walkErr := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if p != root && skipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		data, readErr := os.ReadFile(filepath.Join(p, "go.mod"))
+		if os.IsNotExist(readErr) {
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(root, p)
+		if relErr != nil {
+			return relErr
+		}
+		rel = filepath.ToSlash(rel)
+
+		if readErr != nil {
+			modules = append(modules, Module{RelPath: rel, Error: fmt.Sprintf("read go.mod: %v", readErr)})
+			return nil
+		}
+		file, parseErr := modfile.Parse(data)
+		if parseErr != nil {
+			modules = append(modules, Module{RelPath: rel, Error: fmt.Sprintf("parse go.mod: %v", parseErr)})
+			return nil
+		}
+		if file.Module == nil {
+			modules = append(modules, Module{RelPath: rel, Error: "go.mod has no module directive"})
+			return nil
+		}
+
+		mod := Module{RelPath: rel, ModulePath: file.Module.Path}
+		if file.Go != nil {
+			mod.GoVersion = file.Go.Version
+		}
+		for _, rep := range file.Replace {
+			if rep.NewVersion == "" && !filepath.IsAbs(rep.NewPath) {
+				replaces[rel] = append(replaces[rel], path.Join(rel, filepath.ToSlash(rep.NewPath)))
+			}
+		}
+		modules = append(modules, mod)
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("discover modules: %w", walkErr)
+	}
+
+	byRelPath := make(map[string]string, len(modules))
+	for _, mod := range modules {
+		if mod.Error == "" {
+			byRelPath[mod.RelPath] = mod.ModulePath
+		}
+	}
+	for i, mod := range modules {
+		for _, target := range replaces[mod.RelPath] {
+			if sibling, ok := byRelPath[target]; ok {
+				modules[i].SiblingDeps = append(modules[i].SiblingDeps, sibling)
+			}
+		}
+	}
+
+	return modules, nil
+}
+
+// SelectModule resolves selector, either a path relative to root or a module
+// path, to the directory of one of the modules under root.
+func SelectModule(root, selector string) (string, error) {
+	modules, err := DiscoverModules(root)
+	if err != nil {
+		return "", err
+	}
+
+	rel := path.Clean(filepath.ToSlash(selector))
+	for _, mod := range modules {
+		if mod.RelPath == rel && mod.Error != "" {
+			return "", fmt.Errorf("module %q: %s", selector, mod.Error)
+		}
+		if mod.RelPath == rel || (mod.ModulePath != "" && mod.ModulePath == selector) {
+			return filepath.Join(root, filepath.FromSlash(mod.RelPath)), nil
+		}
+	}
+
+	known := make([]string, 0, len(modules))
+	for _, mod := range modules {
+		known = append(known, mod.RelPath)
+	}
+	return "", fmt.Errorf("module %q not found under %s (have: %s)", selector, root, strings.Join(known, ", "))
+}
+
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" ||
+		strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
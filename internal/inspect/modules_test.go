@@ -0,0 +1,119 @@
+package inspect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeModule(t *testing.T, dir, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+}
+
+func setupMultiModule(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	writeModule(t, root, "module example.com/repo\n\ngo 1.24\n")
+	writeModule(t, filepath.Join(root, "api"), "module example.com/repo/api\n\ngo 1.23\n")
+	writeModule(t, filepath.Join(root, "cmd", "tool"),
+		"module example.com/repo/cmd/tool\n\ngo 1.24\n\n"+
+			"require (\n\texample.com/repo v0.0.0\n\texample.com/repo/api v0.0.0\n)\n\n"+
+			"replace example.com/repo => ../..\n\nreplace example.com/repo/api => ../../api\n"+
+			"replace example.com/other => ../../../other\n")
+	writeModule(t, filepath.Join(root, "vendor", "example.com", "dep"), "module example.com/dep\n")
+	writeModule(t, filepath.Join(root, "testdata", "mod"), "module example.com/testdata\n")
+	writeModule(t, filepath.Join(root, ".hidden"), "module example.com/hidden\n")
+
+	return root
+}
+
+func TestDiscoverModules(t *testing.T) {
+	root := setupMultiModule(t)
+
+	got, err := DiscoverModules(root)
+	if err != nil {
+		t.Fatalf("DiscoverModules() error: %v", err)
+	}
+
+	want := []Module{
+		{RelPath: ".", ModulePath: "example.com/repo", GoVersion: "1.24"},
+		{RelPath: "api", ModulePath: "example.com/repo/api", GoVersion: "1.23"},
+		{RelPath: "cmd/tool", ModulePath: "example.com/repo/cmd/tool", GoVersion: "1.24",
+			SiblingDeps: []string{"example.com/repo", "example.com/repo/api"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiscoverModules():\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestDiscoverModulesBrokenNested(t *testing.T) {
+	root := setupMultiModule(t)
+	writeModule(t, filepath.Join(root, "broken"), "module example.com/repo/broken\n\nrequire (\n")
+	writeModule(t, filepath.Join(root, "nomodule"), "go 1.22\n")
+
+	got, err := DiscoverModules(root)
+	if err != nil {
+		t.Fatalf("DiscoverModules() error: %v", err)
+	}
+	if len(got) != 5 {
+		t.Fatalf("DiscoverModules(): got %+v", got)
+	}
+	for _, mod := range got {
+		broken := mod.RelPath == "broken" || mod.RelPath == "nomodule"
+		if broken != (mod.Error != "") || (broken && mod.ModulePath != "") {
+			t.Errorf("module %s: got %+v", mod.RelPath, mod)
+		}
+	}
+
+	if _, err := SelectModule(root, "broken"); err == nil || !strings.Contains(err.Error(), "parse go.mod") {
+		t.Errorf("SelectModule(broken) error: got %v", err)
+	}
+	if dir, err := SelectModule(root, "api"); err != nil || dir != filepath.Join(root, "api") {
+		t.Errorf("SelectModule(api) next to a broken module: got %q, %v", dir, err)
+	}
+}
+
+func TestSelectModule(t *testing.T) {
+	root := setupMultiModule(t)
+
+	tests := []struct {
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{selector: ".", want: root},
+		{selector: "./cmd/tool", want: filepath.Join(root, "cmd", "tool")},
+		{selector: "api/", want: filepath.Join(root, "api")},
+		{selector: "example.com/repo/api", want: filepath.Join(root, "api")},
+		{selector: "vendor/example.com/dep", wantErr: true},
+		{selector: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			got, err := SelectModule(root, tt.selector)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("SelectModule() want error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectModule() error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("SelectModule() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package inspect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/reservation-v/vlang/internal/modulestxt"
+	"github.com/reservation-v/vlang/internal/rpmver"
+)
+
+// Provide is a module bundled in vendor/. Upstream is its Go version and
+// Version the RPM form of it; both are empty for a module replaced by a
+// local directory.
+type Provide struct {
+	Module   string `json:"module"`
+	Upstream string `json:"upstream,omitempty"`
+	Version  string `json:"version,omitempty"`
+}
+
+// String is the provide as the spec lists it.
+func (p Provide) String() string {
+	s := "bundled(golang(" + p.Module + "))"
+	if p.Version != "" {
+		s += " = " + p.Version
+	}
+	return s
+}
+
+// BundledProvides lists a bundled(golang(<module>)) provide for every
+// module with packages in dir/vendor/modules.txt. A module replaced by
+// another version or module is provided under its own path with the
+// version of the replacement.
+func BundledProvides(dir string) ([]Provide, error) {
+	return vendorProvides(filepath.Join(dir, "vendor"))
+}
+
+func vendorProvides(vendor string) ([]Provide, error) {
+	provides := []Provide{}
+
+	hasVendor, err := hasDir(vendor, ".")
+	if err != nil || !hasVendor {
+		return provides, err
+	}
+	data, err := os.ReadFile(filepath.Join(vendor, "modules.txt"))
+	if os.IsNotExist(err) {
+		return provides, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read vendor/modules.txt: %w", err)
+	}
+	file, err := modulestxt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse vendor/modules.txt: %w", err)
+	}
+
+	for _, mod := range file.Modules {
+		if len(mod.Packages) == 0 {
+			continue
+		}
+		upstream := mod.Version
+		if mod.NewPath != "" {
+			upstream = mod.NewVersion
+		}
+		provide := Provide{Module: mod.Path, Upstream: upstream}
+		if upstream != "" {
+			provide.Version, err = rpmver.ProvidesVersion(upstream)
+			if err != nil {
+				return nil, fmt.Errorf("map version of %s: %w", mod.Path, err)
+			}
+		}
+		provides = append(provides, provide)
+	}
+
+	sort.Slice(provides, func(i, j int) bool { return provides[i].Module < provides[j].Module })
+	return provides, nil
+}
@@ -0,0 +1,51 @@
+package inspect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBundledProvides(t *testing.T) {
+	dir := t.TempDir()
+	modulesTxt := "# github.com/b/lib v1.2.0-rc.1\n## explicit; go 1.20\ngithub.com/b/lib\n" +
+		"# github.com/a/old v1.0.0 => github.com/a/fork v1.1.0\n## explicit\ngithub.com/a/old\n" +
+		"# example.com/local v0.1.0 => ../local\n## explicit\nexample.com/local/pkg\n" +
+		"# golang.org/x/sys v0.0.0-20240101000000-0123456789ab\ngolang.org/x/sys/unix\n" +
+		"# example.com/unused v1.0.0\n## explicit\n" +
+		"# github.com/a/old => github.com/a/fork v1.1.0\n"
+	if err := os.MkdirAll(filepath.Join(dir, "vendor"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "vendor", "modules.txt"), []byte(modulesTxt), 0o644); err != nil {
+		t.Fatalf("write modules.txt: %v", err)
+	}
+
+	got, err := BundledProvides(dir)
+	if err != nil {
+		t.Fatalf("BundledProvides() error: %v", err)
+	}
+	want := []Provide{
+		{Module: "example.com/local"},
+		{Module: "github.com/a/old", Upstream: "v1.1.0", Version: "1.1.0"},
+		{Module: "github.com/b/lib", Upstream: "v1.2.0-rc.1", Version: "1.2.0~rc1"},
+		{Module: "golang.org/x/sys", Upstream: "v0.0.0-20240101000000-0123456789ab", Version: "0.0.0~git20240101000000.0123456789ab"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BundledProvides():\ngot  %+v\nwant %+v", got, want)
+	}
+
+	lines := []string{got[0].String(), got[2].String()}
+	wantLines := []string{"bundled(golang(example.com/local))", "bundled(golang(github.com/b/lib)) = 1.2.0~rc1"}
+	if !reflect.DeepEqual(lines, wantLines) {
+		t.Errorf("String(): got %q, want %q", lines, wantLines)
+	}
+}
+
+func TestBundledProvidesWithoutVendor(t *testing.T) {
+	got, err := BundledProvides(t.TempDir())
+	if err != nil || len(got) != 0 {
+		t.Fatalf("BundledProvides() = %+v, %v; want none", got, err)
+	}
+}
@@ -0,0 +1,100 @@
+package inspect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/reservation-v/vlang/internal/modfile"
+)
+
+type Workspace struct {
+	Path        string   `json:"path"`
+	GoVersion   string   `json:"go_version,omitempty"`
+	Toolchain   string   `json:"toolchain,omitempty"`
+	Use         []string `json:"use"`
+	Replace     []string `json:"replace,omitempty"`
+	IncludesDir bool     `json:"includes_dir"`
+}
+
+// FindWorkFile locates the go.work file the go command would use for dir:
+// GOWORK=off disables workspaces, an explicit GOWORK path wins, otherwise
+// dir and its parents are searched.
+func FindWorkFile(dir string) (string, error) {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return "", nil
+	case "", "auto":
+	default:
+		if !strings.HasSuffix(gowork, ".work") {
+			return "", fmt.Errorf("GOWORK=%s does not name a .work file", gowork)
+		}
+		return filepath.Abs(gowork)
+	}
+
+	for cur := dir; ; {
+		candidate := filepath.Join(cur, "go.work")
+		found, err := hasFile(cur, "go.work")
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return candidate, nil
+		}
+
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return "", nil
+		}
+		cur = parent
+	}
+}
+
+// LoadWorkspace returns the workspace dir belongs to, or nil when there is none.
+func LoadWorkspace(dir string) (*Workspace, error) {
+	workPath, findErr := FindWorkFile(dir)
+	if findErr != nil {
+		return nil, fmt.Errorf("find go.work: %w", findErr)
+	}
+	if workPath == "" {
+		return nil, nil
+	}
+
+	data, readErr := os.ReadFile(workPath)
+	if readErr != nil {
+		return nil, fmt.Errorf("read go.work: %w", readErr)
+	}
+	work, parseErr := modfile.ParseWork(data)
+	if parseErr != nil {
+		return nil, fmt.Errorf("parse %s: %w", workPath, parseErr)
+	}
+
+	ws := &Workspace{Path: workPath, Use: make([]string, 0, len(work.Use))}
+	if work.Go != nil {
+		ws.GoVersion = work.Go.Version
+	}
+	if work.Toolchain != nil {
+		ws.Toolchain = work.Toolchain.Name
+	}
+
+	workDir := filepath.Dir(workPath)
+	for _, use := range work.Use {
+		ws.Use = append(ws.Use, use.Path)
+
+		useDir := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(useDir) {
+			useDir = filepath.Join(workDir, useDir)
+		}
+		if filepath.Clean(useDir) == filepath.Clean(dir) {
+			ws.IncludesDir = true
+		}
+	}
+	for _, rep := range work.Replace {
+		ws.Replace = append(ws.Replace, rep.OldPath+" => "+rep.NewPath)
+	}
+	slices.Sort(ws.Replace)
+
+	return ws, nil
+}
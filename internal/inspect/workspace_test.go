@@ -0,0 +1,79 @@
+package inspect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadWorkspace(t *testing.T) {
+	t.Setenv("GOWORK", "")
+
+	root := t.TempDir()
+	moduleDir := filepath.Join(root, "app")
+	if err := os.MkdirAll(moduleDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	work := "go 1.23\n\nuse (\n\t./app\n\t./lib\n)\n\nreplace example.com/x => ./x\n"
+	if err := os.WriteFile(filepath.Join(root, "go.work"), []byte(work), 0o644); err != nil {
+		t.Fatalf("write go.work: %v", err)
+	}
+
+	ws, err := LoadWorkspace(moduleDir)
+	if err != nil {
+		t.Fatalf("LoadWorkspace() error: %v", err)
+	}
+	if ws == nil {
+		t.Fatalf("LoadWorkspace() got nil workspace")
+	}
+	if ws.Path != filepath.Join(root, "go.work") {
+		t.Errorf("Path: got %q", ws.Path)
+	}
+	if ws.GoVersion != "1.23" {
+		t.Errorf("GoVersion: got %q", ws.GoVersion)
+	}
+	if len(ws.Use) != 2 || ws.Use[0] != "./app" {
+		t.Errorf("Use: got %v", ws.Use)
+	}
+	if !ws.IncludesDir {
+		t.Errorf("IncludesDir: got false, want true")
+	}
+	if len(ws.Replace) != 1 || ws.Replace[0] != "example.com/x => ./x" {
+		t.Errorf("Replace: got %v", ws.Replace)
+	}
+
+	t.Setenv("GOWORK", "off")
+	ws, err = LoadWorkspace(moduleDir)
+	if err != nil || ws != nil {
+		t.Errorf("LoadWorkspace() with GOWORK=off: got %+v, %v", ws, err)
+	}
+}
+
+func TestLoadWorkspaceNone(t *testing.T) {
+	t.Setenv("GOWORK", "")
+
+	ws, err := LoadWorkspace(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadWorkspace() error: %v", err)
+	}
+	if ws != nil {
+		t.Fatalf("LoadWorkspace() got %+v, want nil", ws)
+	}
+}
+
+func TestLoadWorkspaceExplicitPath(t *testing.T) {
+	dir := t.TempDir()
+	workPath := filepath.Join(dir, "custom.work")
+	if err := os.WriteFile(workPath, []byte("go 1.22\n\nuse .\n"), 0o644); err != nil {
+		t.Fatalf("write go.work: %v", err)
+	}
+	t.Setenv("GOWORK", workPath)
+
+	ws, err := LoadWorkspace(dir)
+	if err != nil {
+		t.Fatalf("LoadWorkspace() error: %v", err)
+	}
+	if ws == nil || ws.Path != workPath || !ws.IncludesDir {
+		t.Fatalf("LoadWorkspace() got %+v", ws)
+	}
+}
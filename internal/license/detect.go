@@ -0,0 +1,208 @@
+package license
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/reservation-v/vlang/internal/modulestxt"
+)
+
+// File is a license file and the licenses found in it. Path is
+// slash-separated and relative to the project directory.
+type File struct {
+	Path     string  `json:"path"`
+	Licenses []Match `json:"licenses"`
+}
+
+// Module is a vendored module with the license files at its root.
+type Module struct {
+	Path    string   `json:"path"`
+	Version string   `json:"version"`
+	Files   []File   `json:"files"`
+	IDs     []string `json:"ids"`
+}
+
+type Report struct {
+	// Files and IDs describe the project itself.
+	Files   []File   `json:"files"`
+	IDs     []string `json:"ids"`
+	Modules []Module `json:"modules"`
+
+	// Combined lists every license of the project and its vendored
+	// modules; Expression joins them for the spec License: tag.
+	Combined   []string `json:"combined"`
+	Expression string   `json:"expression"`
+
+	// Unknown lists "." for the project and the paths of vendored modules
+	// with no recognized license or with a license whose -only or
+	// -or-later form their files do not state.
+	Unknown []string `json:"unknown"`
+}
+
+// implied drops a license whose text ships only to complete another one:
+// the LGPL-3.0 is a set of permissions on top of the GPL-3.0. Both sides
+// name licenses without their -only or -or-later suffix.
+var implied = map[string]string{"GPL-3.0": "LGPL-3.0"}
+
+// Detect classifies the license files at the root of dir and at the root
+// of each module listed in dir/vendor/modules.txt.
+func Detect(dir string) (Report, error) {
+	return DetectVendor(dir, filepath.Join(dir, "vendor"))
+}
+
+// DetectVendor is Detect with the vendored modules read from vendor in
+// place of dir/vendor. Their files are still reported under vendor/.
+func DetectVendor(dir, vendor string) (Report, error) {
+	report := Report{Files: []File{}, IDs: []string{}, Modules: []Module{}, Unknown: []string{}}
+
+	var err error
+	var ambiguous bool
+	report.Files, report.IDs, ambiguous, err = classifyDir(dir, ".")
+	if err != nil {
+		return Report{}, err
+	}
+	if len(report.IDs) == 0 || ambiguous {
+		report.Unknown = append(report.Unknown, ".")
+	}
+
+	var data []byte
+	if fi, statErr := os.Stat(vendor); statErr == nil && fi.IsDir() {
+		data, err = os.ReadFile(filepath.Join(vendor, "modules.txt"))
+		if err != nil && !os.IsNotExist(err) {
+			return Report{}, fmt.Errorf("read vendor/modules.txt: %w", err)
+		}
+	}
+	vendored, err := modulestxt.Parse(data)
+	if err != nil {
+		return Report{}, fmt.Errorf("parse vendor/modules.txt: %w", err)
+	}
+	for _, mod := range vendored.Modules {
+		// Modules without packages have nothing in vendor/.
+		if len(mod.Packages) == 0 {
+			continue
+		}
+		version := mod.Version
+		if mod.NewVersion != "" {
+			version = mod.NewVersion
+		}
+		files, ids, ambiguous, err := classifyDir(filepath.Join(vendor, filepath.FromSlash(mod.Path)), path.Join("vendor", mod.Path))
+		if err != nil {
+			return Report{}, err
+		}
+		report.Modules = append(report.Modules, Module{Path: mod.Path, Version: version, Files: files, IDs: ids})
+		if len(ids) == 0 || ambiguous {
+			report.Unknown = append(report.Unknown, mod.Path)
+		}
+	}
+	sort.Slice(report.Modules, func(i, j int) bool { return report.Modules[i].Path < report.Modules[j].Path })
+
+	combined := slices.Clone(report.IDs)
+	for _, mod := range report.Modules {
+		combined = append(combined, mod.IDs...)
+	}
+	report.Combined = dedupe(combined)
+	report.Expression = Expression(report.Combined)
+	return report, nil
+}
+
+// Expression joins ids into an SPDX expression that requires all of them.
+func Expression(ids []string) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if strings.Contains(id, " ") {
+			id = "(" + id + ")"
+		}
+		parts = append(parts, id)
+	}
+	return strings.Join(parts, " and ")
+}
+
+// classifyDir classifies the license files directly in dir, naming them
+// under rel. It reports whether a license of the directory is ambiguous;
+// such licenses are left out of the IDs.
+func classifyDir(dir, rel string) ([]File, []string, bool, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []File{}, []string{}, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("read %s: %w", rel, err)
+	}
+
+	files := []File{}
+	var ids, ambiguous []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !IsLicenseFile(entry.Name()) {
+			continue
+		}
+		name := path.Join(rel, entry.Name())
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("read %s: %w", name, err)
+		}
+		matches := Classify(data)
+		if matches == nil {
+			matches = []Match{}
+		}
+		files = append(files, File{Path: name, Licenses: matches})
+		for _, m := range matches {
+			if m.Ambiguous {
+				ambiguous = append(ambiguous, m.ID)
+			} else {
+				ids = append(ids, m.ID)
+			}
+		}
+	}
+	all := append(slices.Clone(ids), ambiguous...)
+	return files, dropImplied(dedupe(ids), all), len(dropImplied(ambiguous, all)) > 0, nil
+}
+
+// dedupe sorts ids and removes repeats.
+func dedupe(ids []string) []string {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// dropImplied drops the ids implied by another license of the directory,
+// all of whose licenses are in all.
+func dropImplied(ids, all []string) []string {
+	drop := make(map[string]bool)
+	for _, id := range all {
+		for weaker, by := range implied {
+			if family(id) == by {
+				drop[weaker] = true
+			}
+		}
+	}
+	return slices.DeleteFunc(ids, func(id string) bool { return drop[family(id)] })
+}
+
+// family strips the -only or -or-later suffix from id.
+func family(id string) string {
+	for _, suffix := range []string{"-only", "-or-later"} {
+		if base, ok := strings.CutSuffix(id, suffix); ok {
+			return base
+		}
+	}
+	return id
+}
+
+// IsLicenseFile reports whether name is a license, copying or notice file.
+func IsLicenseFile(name string) bool {
+	if strings.HasSuffix(name, ".go") {
+		return false
+	}
+	upper := strings.ToUpper(name)
+	for _, prefix := range []string{"LICENSE", "LICENCE", "COPYING", "NOTICE", "UNLICENSE", "PATENTS"} {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
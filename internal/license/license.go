@@ -0,0 +1,246 @@
+// Package license identifies licenses by comparing license files with the
+// SPDX texts embedded in texts/, without any network access.
+package license
+
+import (
+	"embed"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+//go:embed texts/*.txt
+var textsFS embed.FS
+
+// MinConfidence is the share of a license text's word pairs a file must
+// contain for the license to be reported.
+const MinConfidence = 0.9
+
+// Match is a license found in a file. Ambiguous marks a license SPDX
+// names only with an -only or -or-later suffix when the file does not say
+// which one applies; ID is then the license without the suffix.
+type Match struct {
+	ID         string  `json:"id"`
+	Confidence float64 `json:"confidence"`
+	Ambiguous  bool    `json:"ambiguous,omitempty"`
+}
+
+// versioned are the licenses whose SPDX IDs need an -only or -or-later
+// suffix. The license text is the same either way; the notice that comes
+// with it tells which one applies.
+var versioned = map[string]bool{"GPL-2.0": true, "GPL-3.0": true, "LGPL-2.1": true, "LGPL-3.0": true}
+
+type template struct {
+	id     string
+	bigram map[string]bool
+}
+
+var templates = loadTemplates()
+
+type candidate struct {
+	tmpl   template
+	score  float64
+	shared map[string]bool
+}
+
+func loadTemplates() []template {
+	entries, err := textsFS.ReadDir("texts")
+	if err != nil {
+		panic(err)
+	}
+	list := make([]template, 0, len(entries))
+	for _, entry := range entries {
+		data, err := textsFS.ReadFile("texts/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		list = append(list, template{
+			id:     strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())),
+			bigram: bigrams(normalize(string(data))),
+		})
+	}
+	return list
+}
+
+// IDs lists the licenses Classify knows, without the -only or -or-later
+// suffix of a versioned one.
+func IDs() []string {
+	ids := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		ids = append(ids, tmpl.id)
+	}
+	return ids
+}
+
+var spdxHeader = regexp.MustCompile(`SPDX-License-Identifier:\s*([^\s*/]+(?:\s+(?:AND|OR|WITH)\s+[^\s*/]+)*)`)
+
+// Classify reports the licenses whose texts text contains, best match
+// first. A file holding several license texts matches each of them.
+// SPDX-License-Identifier lines are taken as they are.
+func Classify(text []byte) []Match {
+	var matches []Match
+	for _, m := range spdxHeader.FindAllStringSubmatch(string(text), -1) {
+		matches = append(matches, Match{ID: m[1], Confidence: 1})
+	}
+
+	words := normalize(string(text))
+	have := bigrams(words)
+	var found []candidate
+	for _, tmpl := range templates {
+		if score := coverage(tmpl.bigram, have); score >= MinConfidence {
+			found = append(found, candidate{tmpl: tmpl, score: score, shared: shared(tmpl.bigram, have)})
+		}
+	}
+
+	// A short license often reads like part of a longer one (BSD-2-Clause
+	// within BSD-3-Clause, 0BSD within ISC). Take the candidates that share
+	// the most text with the file first and keep later ones only when they
+	// explain mostly text the earlier ones did not.
+	sort.SliceStable(found, func(i, j int) bool { return len(found[i].shared) > len(found[j].shared) })
+	explained := make(map[string]bool)
+	for _, c := range found {
+		fresh := 0
+		for pair := range c.shared {
+			if !explained[pair] {
+				fresh++
+			}
+		}
+		if float64(fresh) < 0.5*float64(len(c.shared)) {
+			continue
+		}
+		for pair := range c.shared {
+			explained[pair] = true
+		}
+		m := Match{ID: c.tmpl.id, Confidence: c.score}
+		if versioned[m.ID] {
+			if suffix := versionSuffix(words, m.ID[strings.LastIndex(m.ID, "-")+1:]); suffix != "" {
+				m.ID += suffix
+			} else {
+				m.Ambiguous = true
+			}
+		}
+		matches = append(matches, m)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Confidence > matches[j].Confidence })
+	seen := make(map[string]bool, len(matches))
+	return slices.DeleteFunc(matches, func(m Match) bool {
+		dup := seen[m.ID]
+		seen[m.ID] = true
+		return dup
+	})
+}
+
+// normalize lowercases text and splits it into words, dropping copyright
+// lines: they differ between every copy of the same license.
+func normalize(text string) []string {
+	var words []string
+	for _, line := range strings.Split(strings.ToLower(text), "\n") {
+		line = strings.TrimLeft(line, " \t#*/-")
+		if strings.HasPrefix(line, "copyright") || strings.HasPrefix(line, "(c)") || strings.HasPrefix(line, "©") {
+			continue
+		}
+		words = append(words, strings.FieldsFunc(line, func(r rune) bool {
+			return !('a' <= r && r <= 'z' || '0' <= r && r <= '9')
+		})...)
+	}
+	for i, word := range words {
+		if word == "licence" {
+			words[i] = "license"
+		}
+	}
+	return words
+}
+
+// versionSuffix reads the notice in words for whether version of a
+// license applies "only" or "or later". It returns "" when words say
+// neither or both. The "How to Apply These Terms" appendix of the GNU
+// licenses is skipped: its sample notice always says "or later".
+func versionSuffix(words []string, version string) string {
+	for i := range words {
+		if hasWords(words[i:], "how", "to", "apply", "these", "terms") {
+			words = words[:i]
+			break
+		}
+	}
+	forms := [][]string{strings.Split(version, ".")}
+	if short, ok := strings.CutSuffix(version, ".0"); ok {
+		forms = append(forms, []string{short})
+	}
+
+	var only, orLater bool
+	for i := range words {
+		if hasWords(words[i:], "at", "your", "option", "any", "later", "version") {
+			orLater = true
+		}
+		for _, form := range forms {
+			if !versionAt(words, i, form) {
+				continue
+			}
+			rest := words[i+len(form):]
+			switch {
+			case hasWords(rest, "or", "later"):
+				orLater = true
+			case hasWords(rest, "only"), i >= 2 && hasWords(words[i-2:], "only", "version"):
+				only = true
+			}
+		}
+	}
+	switch {
+	case only && !orLater:
+		return "-only"
+	case orLater && !only:
+		return "-or-later"
+	}
+	return ""
+}
+
+// versionAt reports whether words[i:] start with version, written either
+// after "version", "gpl" or "lgpl" or glued to a "v" as in "gplv3".
+func versionAt(words []string, i int, version []string) bool {
+	if len(words)-i < len(version) || !slices.Equal(words[i+1:i+len(version)], version[1:]) {
+		return false
+	}
+	if strings.HasSuffix(words[i], "v"+version[0]) {
+		return true
+	}
+	return words[i] == version[0] && i > 0 && slices.Contains([]string{"version", "gpl", "lgpl"}, words[i-1])
+}
+
+func hasWords(words []string, want ...string) bool {
+	return len(words) >= len(want) && slices.Equal(words[:len(want)], want)
+}
+
+func bigrams(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for i := 1; i < len(words); i++ {
+		set[words[i-1]+" "+words[i]] = true
+	}
+	return set
+}
+
+func shared(want, have map[string]bool) map[string]bool {
+	both := make(map[string]bool)
+	for pair := range want {
+		if have[pair] {
+			both[pair] = true
+		}
+	}
+	return both
+}
+
+// coverage is the share of want found in have.
+func coverage(want, have map[string]bool) float64 {
+	if len(want) == 0 {
+		return 0
+	}
+	n := 0
+	for pair := range want {
+		if have[pair] {
+			n++
+		}
+	}
+	return float64(n) / float64(len(want))
+}
@@ -0,0 +1,240 @@
+package license
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const mitText = `Copyright (c) 2019 Jane Doe
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+associated documentation files (the "Software"), to deal in the Software without restriction,
+including without limitation the rights to use, copy, modify, merge, publish, distribute,
+sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or
+substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT
+NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES
+OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+`
+
+// goBSD is the BSD-3-Clause text as the Go project words it.
+const goBSD = `Copyright 2009 The Go Authors.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are
+met:
+
+   * Redistributions of source code must retain the above copyright
+notice, this list of conditions and the following disclaimer.
+   * Redistributions in binary form must reproduce the above
+copyright notice, this list of conditions and the following disclaimer
+in the documentation and/or other materials provided with the
+distribution.
+   * Neither the name of Google LLC nor the names of its
+contributors may be used to endorse or promote products derived from
+this software without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+"AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+(INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+`
+
+const gplNotice = `This program is free software; you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation; either version 2 of the License, or
+(at your option) any later version.
+
+`
+
+const lgplNotice = `This library is free software: you can redistribute it and/or modify it
+under the terms of the GNU Lesser General Public License as published by the
+Free Software Foundation, either version 3 of the License, or (at your
+option) any later version.
+
+`
+
+func embedded(t *testing.T, id string) string {
+	t.Helper()
+	data, err := textsFS.ReadFile("texts/" + id + ".txt")
+	if err != nil {
+		t.Fatalf("read %s: %v", id, err)
+	}
+	return string(data)
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "mit", text: mitText, want: []string{"MIT"}},
+		{name: "go bsd", text: goBSD, want: []string{"BSD-3-Clause"}},
+		{name: "bsd-2", text: embedded(t, "BSD-2-Clause"), want: []string{"BSD-2-Clause"}},
+		{name: "isc", text: embedded(t, "ISC"), want: []string{"ISC"}},
+		{name: "0bsd", text: embedded(t, "0BSD"), want: []string{"0BSD"}},
+		{
+			name: "apache with appendix",
+			text: embedded(t, "Apache-2.0") + "\nAPPENDIX: How to apply the Apache License to your work.\n",
+			want: []string{"Apache-2.0"},
+		},
+		{name: "two licenses", text: mitText + "\n---\n\n" + goBSD, want: []string{"BSD-3-Clause", "MIT"}},
+		{name: "spdx header", text: "// SPDX-License-Identifier: MIT OR Apache-2.0\n", want: []string{"MIT OR Apache-2.0"}},
+		{name: "header and text", text: "SPDX-License-Identifier: MIT\n\n" + mitText, want: []string{"MIT"}},
+		{name: "unknown", text: "All rights reserved. Do not copy.\n", want: nil},
+		{name: "truncated", text: mitText[:len(mitText)/2], want: nil},
+		{name: "gpl or later", text: gplNotice + embedded(t, "GPL-2.0"), want: []string{"GPL-2.0-or-later"}},
+		{name: "gplv3 only", text: "Licensed under the GPLv3 only.\n\n" + embedded(t, "GPL-3.0"), want: []string{"GPL-3.0-only"}},
+		{name: "lgpl version only", text: "Use only version 2.1 of the LGPL.\n\n" + embedded(t, "LGPL-2.1"), want: []string{"LGPL-2.1-only"}},
+		{name: "gpl ambiguous", text: embedded(t, "GPL-2.0"), want: []string{"GPL-2.0"}},
+		{
+			name: "gpl sample notice",
+			text: embedded(t, "GPL-2.0") + "\nHow to Apply These Terms to Your New Programs\n\n" + gplNotice,
+			want: []string{"GPL-2.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, m := range Classify([]byte(tt.text)) {
+				got = append(got, m.ID)
+			}
+			if strings.Join(dedupe(got), ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("Classify(): got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddedTextsClassifyAsThemselves(t *testing.T) {
+	for _, id := range IDs() {
+		matches := Classify([]byte(embedded(t, id)))
+		if len(matches) != 1 || matches[0].ID != id || matches[0].Ambiguous != versioned[id] {
+			t.Errorf("Classify(%s text) = %+v", id, matches)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"LICENSE":                        mitText,
+		"NOTICE":                         "This product includes software developed at Example.\n",
+		"license.go":                     "package main\n",
+		"vendor/modules.txt":             "# example.com/a v1.0.0\n## explicit\nexample.com/a\n# example.com/b v0.1.0 => example.com/b v0.2.0\n## explicit\nexample.com/b\n# example.com/unused v1.0.0\n## explicit\n",
+		"vendor/example.com/a/LICENSE":   goBSD,
+		"vendor/example.com/a/a.go":      "package a\n",
+		"vendor/example.com/b/README.md": "no license here\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	report, err := Detect(dir)
+	if err != nil {
+		t.Fatalf("Detect() error: %v", err)
+	}
+
+	if got := fileNames(report.Files); !reflect.DeepEqual(got, []string{"LICENSE", "NOTICE"}) {
+		t.Errorf("Files: got %v", got)
+	}
+	if !reflect.DeepEqual(report.IDs, []string{"MIT"}) {
+		t.Errorf("IDs: got %v", report.IDs)
+	}
+	if len(report.Modules) != 2 {
+		t.Fatalf("Modules: got %+v", report.Modules)
+	}
+	a, b := report.Modules[0], report.Modules[1]
+	if a.Path != "example.com/a" || !reflect.DeepEqual(fileNames(a.Files), []string{"vendor/example.com/a/LICENSE"}) ||
+		!reflect.DeepEqual(a.IDs, []string{"BSD-3-Clause"}) {
+		t.Errorf("module a: got %+v", a)
+	}
+	if b.Path != "example.com/b" || b.Version != "v0.2.0" || len(b.Files) != 0 || len(b.IDs) != 0 {
+		t.Errorf("module b: got %+v", b)
+	}
+	if !reflect.DeepEqual(report.Combined, []string{"BSD-3-Clause", "MIT"}) {
+		t.Errorf("Combined: got %v", report.Combined)
+	}
+	if report.Expression != "BSD-3-Clause and MIT" {
+		t.Errorf("Expression: got %q", report.Expression)
+	}
+	if !reflect.DeepEqual(report.Unknown, []string{"example.com/b"}) {
+		t.Errorf("Unknown: got %v", report.Unknown)
+	}
+}
+
+func TestDetectLGPL(t *testing.T) {
+	tests := []struct {
+		name       string
+		notice     string
+		expression string
+		unknown    []string
+	}{
+		{name: "or later", notice: lgplNotice, expression: "LGPL-3.0-or-later", unknown: []string{}},
+		{name: "ambiguous", notice: "", expression: "", unknown: []string{"."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			files := map[string]string{
+				"COPYING":        embedded(t, "GPL-3.0"),
+				"COPYING.LESSER": tt.notice + embedded(t, "LGPL-3.0"),
+			}
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatalf("write %s: %v", name, err)
+				}
+			}
+
+			report, err := Detect(dir)
+			if err != nil {
+				t.Fatalf("Detect() error: %v", err)
+			}
+			if report.Expression != tt.expression {
+				t.Errorf("Expression: got %q, want %q", report.Expression, tt.expression)
+			}
+			if !reflect.DeepEqual(report.Unknown, tt.unknown) {
+				t.Errorf("Unknown: got %v, want %v", report.Unknown, tt.unknown)
+			}
+		})
+	}
+}
+
+func TestExpression(t *testing.T) {
+	got := Expression([]string{"Apache-2.0", "MIT OR Apache-2.0"})
+	if want := "Apache-2.0 and (MIT OR Apache-2.0)"; got != want {
+		t.Fatalf("Expression(): got %q, want %q", got, want)
+	}
+}
+
+func fileNames(files []File) []string {
+	names := []string{}
+	for _, f := range files {
+		names = append(names, f.Path)
+	}
+	return names
+}
@@ -0,0 +1,47 @@
+package modcache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Dir returns the module cache root the go command would use, without
+// invoking the toolchain.
+func Dir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if gopath := os.Getenv("GOPATH"); gopath != "" {
+		return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, "go", "pkg", "mod")
+}
+
+// Escape applies the module cache case encoding: every upper-case letter is
+// replaced by '!' followed by its lower-case form.
+func Escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func ModuleDir(cache, path, version string) string {
+	return filepath.Join(cache, filepath.FromSlash(Escape(path)+"@"+Escape(version)))
+}
+
+func DownloadDir(cache, path string) string {
+	return filepath.Join(cache, "cache", "download", filepath.FromSlash(Escape(path)), "@v")
+}
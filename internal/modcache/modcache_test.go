@@ -0,0 +1,48 @@
+package modcache
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "github.com/a/b", want: "github.com/a/b"},
+		{in: "github.com/BurntSushi/toml", want: "github.com/!burnt!sushi/toml"},
+		{in: "v1.0.0-RC1", want: "v1.0.0-!r!c1"},
+	}
+
+	for _, tt := range tests {
+		if got := Escape(tt.in); got != tt.want {
+			t.Errorf("Escape(%q) got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDir(t *testing.T) {
+	t.Setenv("GOMODCACHE", "/cache")
+	if got := Dir(); got != "/cache" {
+		t.Errorf("Dir() with GOMODCACHE: got %q", got)
+	}
+
+	t.Setenv("GOMODCACHE", "")
+	t.Setenv("GOPATH", "/gp1"+string(filepath.ListSeparator)+"/gp2")
+	if got := Dir(); got != filepath.Join("/gp1", "pkg", "mod") {
+		t.Errorf("Dir() with GOPATH: got %q", got)
+	}
+}
+
+func TestModuleDir(t *testing.T) {
+	got := ModuleDir("/cache", "github.com/BurntSushi/toml", "v1.3.2")
+	if want := filepath.Join("/cache", "github.com", "!burnt!sushi", "toml@v1.3.2"); got != want {
+		t.Errorf("ModuleDir() got %q, want %q", got, want)
+	}
+
+	got = DownloadDir("/cache", "github.com/BurntSushi/toml")
+	if want := filepath.Join("/cache", "cache", "download", "github.com", "!burnt!sushi", "toml", "@v"); got != want {
+		t.Errorf("DownloadDir() got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,312 @@
+package modfile
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Editor applies targeted edits to go.mod content. Lines that are not
+// touched by an edit are written back exactly as they were read.
+type Editor struct {
+	lines []string
+	file  *File
+}
+
+func NewEditor(data []byte) (*Editor, error) {
+	e := &Editor{}
+	if err := e.reset(string(data)); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *Editor) File() *File {
+	return e.file
+}
+
+func (e *Editor) Bytes() []byte {
+	return []byte(strings.Join(e.lines, ""))
+}
+
+func (e *Editor) WriteFile(path string) error {
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, e.Bytes(), perm); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+func (e *Editor) SetGo(version string) error {
+	if !goVersionRE.MatchString(version) {
+		return fmt.Errorf("invalid go version %q", version)
+	}
+	if e.file.Go != nil {
+		return e.rewriteEntry(e.file.Go.Line, "go", version)
+	}
+
+	at := 0
+	if e.file.Module != nil {
+		at = e.file.Module.Line
+	}
+	return e.insertDirective(at, "go "+version)
+}
+
+func (e *Editor) SetToolchain(name string) error {
+	if name == "" {
+		if e.file.Toolchain == nil {
+			return nil
+		}
+		return e.dropLine(e.file.Toolchain.Line)
+	}
+	if name != "default" && !strings.HasPrefix(name, "go") {
+		return fmt.Errorf("invalid toolchain name %q", name)
+	}
+	if e.file.Toolchain != nil {
+		return e.rewriteEntry(e.file.Toolchain.Line, "toolchain", name)
+	}
+
+	at := 0
+	if e.file.Go != nil {
+		at = e.file.Go.Line
+	} else if e.file.Module != nil {
+		at = e.file.Module.Line
+	}
+	return e.insertDirective(at, "toolchain "+name)
+}
+
+func (e *Editor) AddReplace(oldPath, oldVersion, newPath, newVersion string) error {
+	if oldPath == "" || newPath == "" {
+		return fmt.Errorf("replace requires old and new paths")
+	}
+	if newVersion == "" && !IsDirectoryPath(newPath) {
+		return fmt.Errorf("replacement module %q without version must be a directory path", newPath)
+	}
+
+	entry := formatPath(oldPath)
+	if oldVersion != "" {
+		entry += " " + oldVersion
+	}
+	entry += " => " + formatPath(newPath)
+	if newVersion != "" {
+		entry += " " + newVersion
+	}
+
+	for _, rep := range e.file.Replace {
+		if rep.OldPath == oldPath && rep.OldVersion == oldVersion {
+			return e.rewriteEntry(rep.Line, "replace", entry)
+		}
+	}
+
+	lines := make([]int, 0, len(e.file.Replace))
+	for _, rep := range e.file.Replace {
+		lines = append(lines, rep.Line)
+	}
+	return e.addEntry("replace", entry, lines)
+}
+
+func (e *Editor) DropReplace(oldPath, oldVersion string) error {
+	for _, rep := range e.file.Replace {
+		if rep.OldPath == oldPath && rep.OldVersion == oldVersion {
+			return e.dropLine(rep.Line)
+		}
+	}
+	return fmt.Errorf("no replace for %s", joinVersion(oldPath, oldVersion))
+}
+
+func (e *Editor) AddExclude(path, version string) error {
+	if path == "" || version == "" {
+		return fmt.Errorf("exclude requires path and version")
+	}
+	lines := make([]int, 0, len(e.file.Exclude))
+	for _, exc := range e.file.Exclude {
+		if exc.Path == path && exc.Version == version {
+			return nil
+		}
+		lines = append(lines, exc.Line)
+	}
+	return e.addEntry("exclude", formatPath(path)+" "+version, lines)
+}
+
+func (e *Editor) DropExclude(path, version string) error {
+	for _, exc := range e.file.Exclude {
+		if exc.Path == path && exc.Version == version {
+			return e.dropLine(exc.Line)
+		}
+	}
+	return fmt.Errorf("no exclude for %s", joinVersion(path, version))
+}
+
+func (e *Editor) reset(text string) error {
+	file, err := Parse([]byte(text))
+	if err != nil {
+		return err
+	}
+	e.file = file
+	e.lines = strings.SplitAfter(text, "\n")
+	return nil
+}
+
+func (e *Editor) apply(lines []string) error {
+	return e.reset(strings.Join(lines, ""))
+}
+
+func (e *Editor) addEntry(verb, entry string, existing []int) error {
+	for _, block := range e.file.Blocks {
+		if block.Verb == verb && block.Start != block.End {
+			return e.insertLines(block.End-1, "\t"+entry+"\n")
+		}
+	}
+	if len(existing) > 0 {
+		return e.insertLines(existing[len(existing)-1], verb+" "+entry+"\n")
+	}
+	return e.insertDirective(len(e.lines), verb+" "+entry)
+}
+
+// insertDirective adds a top-level directive after line at, separated from
+// its neighbours by a blank line.
+func (e *Editor) insertDirective(at int, text string) error {
+	lines := append([]string{}, e.lines...)
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+
+	if at >= len(lines) {
+		if n := len(lines); n > 0 {
+			if !strings.HasSuffix(lines[n-1], "\n") {
+				lines[n-1] += "\n"
+			}
+			if strings.TrimSpace(lines[n-1]) != "" {
+				lines = append(lines, "\n")
+			}
+		}
+		lines = append(lines, text+"\n")
+		return e.apply(lines)
+	}
+
+	insert := []string{text + "\n"}
+	if at > 0 {
+		insert = append([]string{"\n"}, insert...)
+	}
+	if strings.TrimSpace(lines[at]) != "" {
+		insert = append(insert, "\n")
+	}
+	return e.apply(splice(lines, at, 0, insert...))
+}
+
+func (e *Editor) insertLines(after int, text string) error {
+	lines := append([]string{}, e.lines...)
+	if after > 0 && !strings.HasSuffix(lines[after-1], "\n") {
+		lines[after-1] += "\n"
+	}
+	return e.apply(splice(lines, after, 0, text))
+}
+
+func (e *Editor) rewriteEntry(line int, verb, entry string) error {
+	raw := e.lines[line-1]
+	body := strings.TrimRight(raw, "\r\n")
+	eol := raw[len(body):]
+
+	indent := body[:len(body)-len(strings.TrimLeft(body, " \t"))]
+	_, comment := splitComment(body)
+
+	text := indent
+	if e.blockAt(line) == nil {
+		text += verb + " "
+	}
+	text += entry
+	if comment != "" {
+		text += " " + comment
+	}
+
+	lines := append([]string{}, e.lines...)
+	lines[line-1] = text + eol
+	return e.apply(lines)
+}
+
+func (e *Editor) dropLine(line int) error {
+	lines := append([]string{}, e.lines...)
+
+	if block := e.blockAt(line); block != nil {
+		entries := 0
+		for i := block.Start + 1; i < block.End; i++ {
+			code, _ := splitComment(lines[i-1])
+			if strings.TrimSpace(code) != "" {
+				entries++
+			}
+		}
+		if entries > 1 {
+			return e.apply(splice(lines, line-1, 1))
+		}
+		return e.apply(dropWithBlank(lines, block.Start, block.End))
+	}
+
+	return e.apply(dropWithBlank(lines, line, line))
+}
+
+func (e *Editor) blockAt(line int) *Block {
+	for _, block := range e.file.Blocks {
+		if line > block.Start && line < block.End {
+			return block
+		}
+	}
+	return nil
+}
+
+// dropWithBlank removes lines start..end (1-based, inclusive) and one of the
+// surrounding blank lines so that removal does not leave a double gap.
+func dropWithBlank(lines []string, start, end int) []string {
+	from, to := start-1, end
+	prevBlank := from > 0 && strings.TrimSpace(lines[from-1]) == ""
+	nextBlank := to >= len(lines) || strings.TrimSpace(lines[to]) == ""
+	if prevBlank && nextBlank {
+		from--
+	}
+	return splice(lines, from, to-from)
+}
+
+func splice(lines []string, at, del int, insert ...string) []string {
+	out := make([]string, 0, len(lines)-del+len(insert))
+	out = append(out, lines[:at]...)
+	out = append(out, insert...)
+	out = append(out, lines[at+del:]...)
+	return out
+}
+
+func splitComment(line string) (code, comment string) {
+	inQuote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inQuote != 0:
+			if c == '\\' && inQuote == '"' {
+				i++
+			} else if c == inQuote {
+				inQuote = 0
+			}
+		case c == '"' || c == '`':
+			inQuote = c
+		case strings.HasPrefix(line[i:], "//"):
+			return strings.TrimRight(line[:i], " \t"), strings.TrimRight(line[i:], "\r\n")
+		}
+	}
+	return line, ""
+}
+
+func formatPath(path string) string {
+	if strings.ContainsAny(path, " \t\"'`()[],") || strings.Contains(path, "//") {
+		return strconv.Quote(path)
+	}
+	return path
+}
+
+func joinVersion(path, version string) string {
+	if version == "" {
+		return path
+	}
+	return path + "@" + version
+}
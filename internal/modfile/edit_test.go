@@ -0,0 +1,247 @@
+package modfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const editBase = `module github.com/example/project // main module
+
+go 1.24 // keep me
+
+require (
+	github.com/a/b v1.2.3
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/a/b => ../b // local fork
+`
+
+func TestEditorRoundTrip(t *testing.T) {
+	inputs := []string{
+		editBase,
+		"module m\r\n\r\ngo 1.22\r\n",
+		"module m\n\n\n// trailing comment without newline",
+		fullGoMod,
+	}
+
+	for _, in := range inputs {
+		e, err := NewEditor([]byte(in))
+		if err != nil {
+			t.Fatalf("NewEditor() unexpected error: %v", err)
+		}
+		if got := string(e.Bytes()); got != in {
+			t.Errorf("round trip changed content:\ngot:\n%s\nwant:\n%s", got, in)
+		}
+	}
+}
+
+func TestEditor(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		edit func(e *Editor) error
+		want string
+	}{
+		{
+			name: "set_go_keeps_comment",
+			in:   editBase,
+			edit: func(e *Editor) error { return e.SetGo("1.22") },
+			want: `module github.com/example/project // main module
+
+go 1.22 // keep me
+
+require (
+	github.com/a/b v1.2.3
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/a/b => ../b // local fork
+`,
+		},
+		{
+			name: "set_go_missing",
+			in:   "module m\n\nrequire a v1.0.0\n",
+			edit: func(e *Editor) error { return e.SetGo("1.21") },
+			want: "module m\n\ngo 1.21\n\nrequire a v1.0.0\n",
+		},
+		{
+			name: "set_toolchain_missing",
+			in:   "module m\n\ngo 1.21\n\nrequire a v1.0.0\n",
+			edit: func(e *Editor) error { return e.SetToolchain("go1.21.5") },
+			want: "module m\n\ngo 1.21\n\ntoolchain go1.21.5\n\nrequire a v1.0.0\n",
+		},
+		{
+			name: "drop_toolchain",
+			in:   "module m\n\ngo 1.21\n\ntoolchain go1.21.5\n\nrequire a v1.0.0\n",
+			edit: func(e *Editor) error { return e.SetToolchain("") },
+			want: "module m\n\ngo 1.21\n\nrequire a v1.0.0\n",
+		},
+		{
+			name: "add_replace_after_existing",
+			in:   editBase,
+			edit: func(e *Editor) error {
+				return e.AddReplace("golang.org/x/text", "", "github.com/fork/text", "v0.15.0")
+			},
+			want: `module github.com/example/project // main module
+
+go 1.24 // keep me
+
+require (
+	github.com/a/b v1.2.3
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/a/b => ../b // local fork
+replace golang.org/x/text => github.com/fork/text v0.15.0
+`,
+		},
+		{
+			name: "add_replace_updates_existing",
+			in:   editBase,
+			edit: func(e *Editor) error { return e.AddReplace("github.com/a/b", "", "../other", "") },
+			want: `module github.com/example/project // main module
+
+go 1.24 // keep me
+
+require (
+	github.com/a/b v1.2.3
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/a/b => ../other // local fork
+`,
+		},
+		{
+			name: "add_replace_into_block",
+			in:   "module m\n\nreplace (\n\ta => ./a\n)\n",
+			edit: func(e *Editor) error { return e.AddReplace("b", "v1.0.0", "c", "v1.0.1") },
+			want: "module m\n\nreplace (\n\ta => ./a\n\tb v1.0.0 => c v1.0.1\n)\n",
+		},
+		{
+			name: "add_replace_new",
+			in:   "module m\n\ngo 1.21\n",
+			edit: func(e *Editor) error { return e.AddReplace("a", "", "./a", "") },
+			want: "module m\n\ngo 1.21\n\nreplace a => ./a\n",
+		},
+		{
+			name: "drop_replace_single",
+			in:   editBase,
+			edit: func(e *Editor) error { return e.DropReplace("github.com/a/b", "") },
+			want: `module github.com/example/project // main module
+
+go 1.24 // keep me
+
+require (
+	github.com/a/b v1.2.3
+	golang.org/x/text v0.14.0 // indirect
+)
+`,
+		},
+		{
+			name: "drop_replace_in_block",
+			in:   "module m\n\nreplace (\n\ta => ./a\n\tb => ./b\n)\n",
+			edit: func(e *Editor) error { return e.DropReplace("a", "") },
+			want: "module m\n\nreplace (\n\tb => ./b\n)\n",
+		},
+		{
+			name: "drop_last_in_block_removes_block",
+			in:   "module m\n\nreplace (\n\ta => ./a\n)\n\ngo 1.21\n",
+			edit: func(e *Editor) error { return e.DropReplace("a", "") },
+			want: "module m\n\ngo 1.21\n",
+		},
+		{
+			name: "add_exclude_and_drop",
+			in:   "module m\n",
+			edit: func(e *Editor) error {
+				if err := e.AddExclude("a", "v1.0.0"); err != nil {
+					return err
+				}
+				if err := e.AddExclude("b", "v2.0.0"); err != nil {
+					return err
+				}
+				return e.DropExclude("a", "v1.0.0")
+			},
+			want: "module m\n\nexclude b v2.0.0\n",
+		},
+		{
+			name: "add_exclude_quoted_path",
+			in:   "module m\n",
+			edit: func(e *Editor) error { return e.AddExclude("a b", "v1.0.0") },
+			want: "module m\n\nexclude \"a b\" v1.0.0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEditor([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("NewEditor() unexpected error: %v", err)
+			}
+			if err := tt.edit(e); err != nil {
+				t.Fatalf("edit unexpected error: %v", err)
+			}
+			if got := string(e.Bytes()); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditorErrors(t *testing.T) {
+	e, err := NewEditor([]byte(editBase))
+	if err != nil {
+		t.Fatalf("NewEditor() unexpected error: %v", err)
+	}
+
+	if err := e.SetGo("go1.22"); err == nil {
+		t.Errorf("SetGo(go1.22) want error, got nil")
+	}
+	if err := e.AddReplace("a", "", "b", ""); err == nil {
+		t.Errorf("AddReplace without version want error, got nil")
+	}
+	if err := e.DropReplace("missing", ""); err == nil {
+		t.Errorf("DropReplace(missing) want error, got nil")
+	}
+	if err := e.DropExclude("missing", "v1.0.0"); err == nil {
+		t.Errorf("DropExclude(missing) want error, got nil")
+	}
+	if got := string(e.Bytes()); got != editBase {
+		t.Errorf("failed edits changed content:\n%s", got)
+	}
+}
+
+func TestEditorWriteFileKeepsMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+	if err := os.WriteFile(path, []byte("module m\n\ngo 1.24\n"), 0o600); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read go.mod: %v", err)
+	}
+	e, err := NewEditor(data)
+	if err != nil {
+		t.Fatalf("NewEditor() unexpected error: %v", err)
+	}
+	if err := e.SetGo("1.22"); err != nil {
+		t.Fatalf("SetGo() unexpected error: %v", err)
+	}
+	if err := e.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat go.mod: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode: got %v, want %v", info.Mode().Perm(), os.FileMode(0o600))
+	}
+	got, _ := os.ReadFile(path)
+	if string(got) != "module m\n\ngo 1.22\n" {
+		t.Errorf("content: got %q", got)
+	}
+}
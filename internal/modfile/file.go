@@ -0,0 +1,121 @@
+package modfile
+
+import "github.com/reservation-v/vlang/internal/semver"
+
+type File struct {
+	Module    *Module    `json:"module,omitempty"`
+	Go        *Go        `json:"go,omitempty"`
+	Toolchain *Toolchain `json:"toolchain,omitempty"`
+	Godebug   []*Godebug `json:"godebug,omitempty"`
+	Require   []*Require `json:"require,omitempty"`
+	Replace   []*Replace `json:"replace,omitempty"`
+	Exclude   []*Exclude `json:"exclude,omitempty"`
+	Retract   []*Retract `json:"retract,omitempty"`
+	Tool      []*Tool    `json:"tool,omitempty"`
+	Ignore    []*Ignore  `json:"ignore,omitempty"`
+	Blocks    []*Block   `json:"-"`
+}
+
+type Module struct {
+	Path       string `json:"path"`
+	Deprecated string `json:"deprecated,omitempty"`
+	Line       int    `json:"line"`
+}
+
+type Go struct {
+	Version string `json:"version"`
+	Line    int    `json:"line"`
+}
+
+type Toolchain struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+}
+
+type Godebug struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Line  int    `json:"line"`
+}
+
+type Require struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Indirect bool   `json:"indirect"`
+	Line     int    `json:"line"`
+}
+
+type Replace struct {
+	OldPath    string `json:"old_path"`
+	OldVersion string `json:"old_version,omitempty"`
+	NewPath    string `json:"new_path"`
+	NewVersion string `json:"new_version,omitempty"`
+	Line       int    `json:"line"`
+}
+
+type Exclude struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	Line    int    `json:"line"`
+}
+
+type Retract struct {
+	Low       string `json:"low"`
+	High      string `json:"high"`
+	Rationale string `json:"rationale,omitempty"`
+	Line      int    `json:"line"`
+}
+
+type Tool struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+}
+
+type Ignore struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+}
+
+// Block is a parenthesized group of directives; Start is the line with
+// "verb (" and End is the line with the closing ")".
+type Block struct {
+	Verb  string
+	Start int
+	End   int
+}
+
+func (f *File) RequireFor(path string) *Require {
+	for _, req := range f.Require {
+		if req.Path == path {
+			return req
+		}
+	}
+	return nil
+}
+
+// ReplacementFor returns the replace directive that applies to path@version,
+// preferring a version-specific replacement over a wildcard one.
+func (f *File) ReplacementFor(path, version string) *Replace {
+	var wildcard *Replace
+	for _, rep := range f.Replace {
+		if rep.OldPath != path {
+			continue
+		}
+		if rep.OldVersion == version && version != "" {
+			return rep
+		}
+		if rep.OldVersion == "" {
+			wildcard = rep
+		}
+	}
+	return wildcard
+}
+
+func (f *File) Retracted(version string) *Retract {
+	for _, ret := range f.Retract {
+		if semver.InInterval(version, ret.Low, ret.High) {
+			return ret
+		}
+	}
+	return nil
+}
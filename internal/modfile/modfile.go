@@ -8,30 +8,26 @@ import (
 func parseDirective(data []byte, key string) (string, error) {
 	stringArr := strings.Split(string(data), "\n")
 
-	for _, raw := range stringArr {
-		line := strings.TrimSpace(raw)
-
-		if idx := strings.Index(line, "//"); idx >= 0 {
-			line = strings.TrimSpace(line[:idx])
-		}
-		if line == "" {
-			continue
-		}
-
-		fields := strings.Fields(line)
-		if len(fields) == 0 {
+	for i, raw := range stringArr {
+		line := strings.TrimSuffix(raw, "\r")
+
+		ln, lexErr := lexOne(line, i+1)
+		if lexErr != nil {
+			if fields := strings.Fields(line); len(fields) > 0 && fields[0] == key {
+				return "", fmt.Errorf("%s directive malformed: %w", key, lexErr)
+			}
 			continue
 		}
-		if fields[0] != key {
+		if len(ln.tokens) == 0 || !ln.tokens[0].is(key) {
 			continue
 		}
-		if len(fields) != 2 {
+		if len(ln.tokens) != 2 {
 			return "", fmt.Errorf("%s directive malformed", key)
 		}
-		if fields[1] == "" {
+		if ln.tokens[1].text == "" {
 			return "", fmt.Errorf("%s directive has empty value", key)
 		}
-		return fields[1], nil
+		return ln.tokens[1].text, nil
 	}
 
 	return "", fmt.Errorf("%s not found in go.mod", key)
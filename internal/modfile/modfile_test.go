@@ -51,6 +51,18 @@ func TestParseModulePath(t *testing.T) {
 			want:    "github.com/a/b",
 			wantErr: false,
 		},
+		{
+			name:    "ok_quoted",
+			in:      "module \"github.com/a/b\"\n",
+			want:    "github.com/a/b",
+			wantErr: false,
+		},
+		{
+			name:    "ok_other_line_unterminated_quote",
+			in:      "module github.com/a/b\nrequire \"x v1.0.0\n",
+			want:    "github.com/a/b",
+			wantErr: false,
+		},
 		{
 			name:    "err_no_module",
 			in:      "go 1.22\nrequire example.com/x v1.0.0\n",
@@ -75,6 +87,12 @@ func TestParseModulePath(t *testing.T) {
 			want:    "",
 			wantErr: true,
 		},
+		{
+			name:    "err_unterminated_quote",
+			in:      "module \"github.com/a/b\n",
+			want:    "",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,379 @@
+package modfile
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var goVersionRE = regexp.MustCompile(`^([1-9][0-9]*)\.(0|[1-9][0-9]*)(\.(0|[1-9][0-9]*))?((rc|beta)[1-9][0-9]*)?$`)
+
+type token struct {
+	text   string
+	quoted bool
+}
+
+type lexLine struct {
+	num     int
+	tokens  []token
+	comment string
+}
+
+func (t token) is(s string) bool {
+	return !t.quoted && t.text == s
+}
+
+type addFunc func(verb string, args []token, comment string, pending []string, line int) error
+
+func Parse(data []byte) (*File, error) {
+	file := &File{}
+	blocks, err := parseLines(data, blockAllowed, file.add)
+	if err != nil {
+		return nil, err
+	}
+	file.Blocks = blocks
+	return file, nil
+}
+
+func parseLines(data []byte, allowBlock func(string) bool, add addFunc) ([]*Block, error) {
+	lines, lexErr := lex(data)
+	if lexErr != nil {
+		return nil, lexErr
+	}
+
+	var (
+		blocks    []*Block
+		blockVerb string
+		block     *Block
+		pending   []string
+	)
+
+	for _, ln := range lines {
+		if len(ln.tokens) == 0 {
+			if ln.comment != "" {
+				pending = append(pending, ln.comment)
+			} else {
+				pending = nil
+			}
+			continue
+		}
+
+		if block != nil {
+			if len(ln.tokens) == 1 && ln.tokens[0].is(")") {
+				block.End = ln.num
+				blocks = append(blocks, block)
+				block = nil
+				blockVerb = ""
+				pending = nil
+				continue
+			}
+			if err := add(blockVerb, ln.tokens, ln.comment, pending, ln.num); err != nil {
+				return nil, err
+			}
+			pending = nil
+			continue
+		}
+
+		verb := ln.tokens[0]
+		if verb.quoted {
+			return nil, fmt.Errorf("line %d: unexpected quoted string %q", ln.num, verb.text)
+		}
+		args := ln.tokens[1:]
+
+		if len(args) >= 1 && args[0].is("(") {
+			if !allowBlock(verb.text) {
+				return nil, fmt.Errorf("line %d: %s directive does not support blocks", ln.num, verb.text)
+			}
+			switch {
+			case len(args) == 1:
+				block = &Block{Verb: verb.text, Start: ln.num}
+				blockVerb = verb.text
+			case len(args) == 2 && args[1].is(")"):
+				blocks = append(blocks, &Block{Verb: verb.text, Start: ln.num, End: ln.num})
+			default:
+				return nil, fmt.Errorf("line %d: unexpected tokens after %s (", ln.num, verb.text)
+			}
+			pending = nil
+			continue
+		}
+
+		if err := add(verb.text, args, ln.comment, pending, ln.num); err != nil {
+			return nil, err
+		}
+		pending = nil
+	}
+
+	if block != nil {
+		return nil, fmt.Errorf("line %d: %s block is not closed", block.Start, block.Verb)
+	}
+
+	return blocks, nil
+}
+
+func blockAllowed(verb string) bool {
+	switch verb {
+	case "require", "replace", "exclude", "retract", "godebug", "tool", "ignore":
+		return true
+	}
+	return false
+}
+
+func (f *File) add(verb string, args []token, comment string, pending []string, line int) error {
+	if err := checkParens(verb, args, line); err != nil {
+		return err
+	}
+
+	switch verb {
+	case "module":
+		if f.Module != nil {
+			return fmt.Errorf("line %d: repeated module directive (first on line %d)", line, f.Module.Line)
+		}
+		if len(args) != 1 || args[0].text == "" {
+			return fmt.Errorf("line %d: module directive malformed", line)
+		}
+		f.Module = &Module{
+			Path:       args[0].text,
+			Deprecated: deprecation(comment, pending),
+			Line:       line,
+		}
+
+	case "go":
+		if f.Go != nil {
+			return fmt.Errorf("line %d: repeated go directive (first on line %d)", line, f.Go.Line)
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("line %d: go directive malformed", line)
+		}
+		if !goVersionRE.MatchString(args[0].text) {
+			return fmt.Errorf("line %d: invalid go version %q", line, args[0].text)
+		}
+		f.Go = &Go{Version: args[0].text, Line: line}
+
+	case "toolchain":
+		if f.Toolchain != nil {
+			return fmt.Errorf("line %d: repeated toolchain directive (first on line %d)", line, f.Toolchain.Line)
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("line %d: toolchain directive malformed", line)
+		}
+		name := args[0].text
+		if name != "default" && !strings.HasPrefix(name, "go") {
+			return fmt.Errorf("line %d: invalid toolchain name %q", line, name)
+		}
+		f.Toolchain = &Toolchain{Name: name, Line: line}
+
+	case "godebug":
+		if len(args) != 1 {
+			return fmt.Errorf("line %d: godebug directive malformed", line)
+		}
+		key, value, ok := strings.Cut(args[0].text, "=")
+		if !ok || key == "" || value == "" {
+			return fmt.Errorf("line %d: godebug setting must be key=value", line)
+		}
+		f.Godebug = append(f.Godebug, &Godebug{Key: key, Value: value, Line: line})
+
+	case "require":
+		if len(args) != 2 {
+			return fmt.Errorf("line %d: require directive malformed", line)
+		}
+		f.Require = append(f.Require, &Require{
+			Path:     args[0].text,
+			Version:  args[1].text,
+			Indirect: isIndirect(comment),
+			Line:     line,
+		})
+
+	case "replace":
+		rep, err := parseReplace(args, line)
+		if err != nil {
+			return err
+		}
+		f.Replace = append(f.Replace, rep)
+
+	case "exclude":
+		if len(args) != 2 {
+			return fmt.Errorf("line %d: exclude directive malformed", line)
+		}
+		f.Exclude = append(f.Exclude, &Exclude{Path: args[0].text, Version: args[1].text, Line: line})
+
+	case "retract":
+		ret, err := parseRetract(args, line)
+		if err != nil {
+			return err
+		}
+		ret.Rationale = rationale(comment, pending)
+		f.Retract = append(f.Retract, ret)
+
+	case "tool":
+		if len(args) != 1 {
+			return fmt.Errorf("line %d: tool directive malformed", line)
+		}
+		f.Tool = append(f.Tool, &Tool{Path: args[0].text, Line: line})
+
+	case "ignore":
+		if len(args) != 1 {
+			return fmt.Errorf("line %d: ignore directive malformed", line)
+		}
+		f.Ignore = append(f.Ignore, &Ignore{Path: args[0].text, Line: line})
+
+	default:
+		return fmt.Errorf("line %d: unknown directive %q", line, verb)
+	}
+
+	return nil
+}
+
+func checkParens(verb string, args []token, line int) error {
+	for _, arg := range args {
+		if !arg.quoted && (arg.text == "(" || arg.text == ")") {
+			return fmt.Errorf("line %d: unexpected %q in %s directive", line, arg.text, verb)
+		}
+	}
+	return nil
+}
+
+func parseReplace(args []token, line int) (*Replace, error) {
+	arrow := -1
+	for i, arg := range args {
+		if arg.is("=>") {
+			arrow = i
+			break
+		}
+	}
+	if arrow < 0 {
+		return nil, fmt.Errorf("line %d: replace directive missing =>", line)
+	}
+
+	left, right := args[:arrow], args[arrow+1:]
+	if len(left) < 1 || len(left) > 2 || len(right) < 1 || len(right) > 2 {
+		return nil, fmt.Errorf("line %d: replace directive malformed", line)
+	}
+
+	rep := &Replace{OldPath: left[0].text, NewPath: right[0].text, Line: line}
+	if len(left) == 2 {
+		rep.OldVersion = left[1].text
+	}
+	if len(right) == 2 {
+		rep.NewVersion = right[1].text
+		if IsDirectoryPath(rep.NewPath) {
+			return nil, fmt.Errorf("line %d: replacement directory %q must not have a version", line, rep.NewPath)
+		}
+	} else if !IsDirectoryPath(rep.NewPath) {
+		return nil, fmt.Errorf("line %d: replacement module %q without version must be a directory path", line, rep.NewPath)
+	}
+
+	return rep, nil
+}
+
+func parseRetract(args []token, line int) (*Retract, error) {
+	if len(args) == 1 {
+		return &Retract{Low: args[0].text, High: args[0].text, Line: line}, nil
+	}
+	if len(args) == 5 && args[0].is("[") && args[2].is(",") && args[4].is("]") {
+		return &Retract{Low: args[1].text, High: args[3].text, Line: line}, nil
+	}
+	return nil, fmt.Errorf("line %d: retract directive malformed", line)
+}
+
+func IsDirectoryPath(path string) bool {
+	return path == "." || path == ".." ||
+		strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") ||
+		strings.HasPrefix(path, "/") ||
+		strings.HasPrefix(path, `.\`) || strings.HasPrefix(path, `..\`)
+}
+
+func isIndirect(comment string) bool {
+	return comment == "indirect" || strings.HasPrefix(comment, "indirect;")
+}
+
+func deprecation(comment string, pending []string) string {
+	for _, c := range append(append([]string{}, pending...), comment) {
+		if rest, ok := strings.CutPrefix(c, "Deprecated:"); ok {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return ""
+}
+
+func rationale(comment string, pending []string) string {
+	if len(pending) > 0 {
+		return strings.Join(pending, "\n")
+	}
+	return comment
+}
+
+func lex(data []byte) ([]lexLine, error) {
+	raw := strings.Split(string(data), "\n")
+	lines := make([]lexLine, 0, len(raw))
+
+	for i, text := range raw {
+		text = strings.TrimSuffix(text, "\r")
+		ln, err := lexOne(text, i+1)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, ln)
+	}
+
+	return lines, nil
+}
+
+func lexOne(text string, num int) (lexLine, error) {
+	ln := lexLine{num: num}
+
+	for pos := 0; pos < len(text); {
+		c := text[pos]
+		switch {
+		case c == ' ' || c == '\t':
+			pos++
+
+		case strings.HasPrefix(text[pos:], "//"):
+			ln.comment = strings.TrimSpace(text[pos+2:])
+			return ln, nil
+
+		case c == '"':
+			end := pos + 1
+			for end < len(text) && text[end] != '"' {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(text) {
+				return lexLine{}, fmt.Errorf("line %d: unterminated quoted string", num)
+			}
+			unquoted, err := strconv.Unquote(text[pos : end+1])
+			if err != nil {
+				return lexLine{}, fmt.Errorf("line %d: invalid quoted string: %w", num, err)
+			}
+			ln.tokens = append(ln.tokens, token{text: unquoted, quoted: true})
+			pos = end + 1
+
+		case c == '`':
+			end := strings.IndexByte(text[pos+1:], '`')
+			if end < 0 {
+				return lexLine{}, fmt.Errorf("line %d: unterminated raw string", num)
+			}
+			ln.tokens = append(ln.tokens, token{text: text[pos+1 : pos+1+end], quoted: true})
+			pos += end + 2
+
+		case strings.ContainsRune("()[],", rune(c)):
+			ln.tokens = append(ln.tokens, token{text: string(c)})
+			pos++
+
+		default:
+			end := pos
+			for end < len(text) {
+				if strings.ContainsRune(" \t()[],\"`", rune(text[end])) || strings.HasPrefix(text[end:], "//") {
+					break
+				}
+				end++
+			}
+			ln.tokens = append(ln.tokens, token{text: text[pos:end]})
+			pos = end
+		}
+	}
+
+	return ln, nil
+}
@@ -0,0 +1,202 @@
+package modfile
+
+import "testing"
+
+const fullGoMod = `// header comment
+module github.com/example/project // Deprecated: use example.com/project/v2
+
+go 1.23.0
+
+toolchain go1.24.2
+
+godebug default=go1.21
+
+godebug (
+	panicnil=1
+	asynctimerchan=0
+)
+
+require github.com/single/dep v1.0.0
+
+require (
+	github.com/a/b v1.2.3
+	"github.com/quoted/path" v0.1.0 // indirect
+	golang.org/x/text v0.14.0 // indirect; pulled by b
+)
+
+replace github.com/a/b => ../b
+
+replace (
+	github.com/a/c v1.0.0 => github.com/fork/c v1.0.1
+	github.com/a/d => ./third_party/d
+)
+
+exclude github.com/a/b v1.2.2
+
+retract (
+	// broken build
+	v1.0.1
+	[v1.1.0, v1.1.5] // security issue
+)
+
+tool golang.org/x/tools/cmd/stringer
+
+ignore ./node_modules
+`
+
+func TestParseFull(t *testing.T) {
+	f, err := Parse([]byte(fullGoMod))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	if f.Module == nil || f.Module.Path != "github.com/example/project" || f.Module.Line != 2 {
+		t.Fatalf("Module: got %+v", f.Module)
+	}
+	if f.Module.Deprecated != "use example.com/project/v2" {
+		t.Errorf("Module.Deprecated: got %q", f.Module.Deprecated)
+	}
+	if f.Go == nil || f.Go.Version != "1.23.0" || f.Go.Line != 4 {
+		t.Errorf("Go: got %+v", f.Go)
+	}
+	if f.Toolchain == nil || f.Toolchain.Name != "go1.24.2" || f.Toolchain.Line != 6 {
+		t.Errorf("Toolchain: got %+v", f.Toolchain)
+	}
+
+	if len(f.Godebug) != 3 {
+		t.Fatalf("Godebug: got %d entries, want 3", len(f.Godebug))
+	}
+	if f.Godebug[1].Key != "panicnil" || f.Godebug[1].Value != "1" || f.Godebug[1].Line != 11 {
+		t.Errorf("Godebug[1]: got %+v", f.Godebug[1])
+	}
+
+	wantRequire := []Require{
+		{Path: "github.com/single/dep", Version: "v1.0.0", Line: 15},
+		{Path: "github.com/a/b", Version: "v1.2.3", Line: 18},
+		{Path: "github.com/quoted/path", Version: "v0.1.0", Indirect: true, Line: 19},
+		{Path: "golang.org/x/text", Version: "v0.14.0", Indirect: true, Line: 20},
+	}
+	if len(f.Require) != len(wantRequire) {
+		t.Fatalf("Require: got %d entries, want %d", len(f.Require), len(wantRequire))
+	}
+	for i, want := range wantRequire {
+		if *f.Require[i] != want {
+			t.Errorf("Require[%d]: got %+v, want %+v", i, *f.Require[i], want)
+		}
+	}
+
+	wantReplace := []Replace{
+		{OldPath: "github.com/a/b", NewPath: "../b", Line: 23},
+		{OldPath: "github.com/a/c", OldVersion: "v1.0.0", NewPath: "github.com/fork/c", NewVersion: "v1.0.1", Line: 26},
+		{OldPath: "github.com/a/d", NewPath: "./third_party/d", Line: 27},
+	}
+	if len(f.Replace) != len(wantReplace) {
+		t.Fatalf("Replace: got %d entries, want %d", len(f.Replace), len(wantReplace))
+	}
+	for i, want := range wantReplace {
+		if *f.Replace[i] != want {
+			t.Errorf("Replace[%d]: got %+v, want %+v", i, *f.Replace[i], want)
+		}
+	}
+
+	if len(f.Exclude) != 1 || f.Exclude[0].Version != "v1.2.2" || f.Exclude[0].Line != 30 {
+		t.Errorf("Exclude: got %+v", f.Exclude)
+	}
+
+	if len(f.Retract) != 2 {
+		t.Fatalf("Retract: got %d entries, want 2", len(f.Retract))
+	}
+	if r := f.Retract[0]; r.Low != "v1.0.1" || r.High != "v1.0.1" || r.Rationale != "broken build" || r.Line != 34 {
+		t.Errorf("Retract[0]: got %+v", r)
+	}
+	if r := f.Retract[1]; r.Low != "v1.1.0" || r.High != "v1.1.5" || r.Rationale != "security issue" || r.Line != 35 {
+		t.Errorf("Retract[1]: got %+v", r)
+	}
+
+	if len(f.Tool) != 1 || f.Tool[0].Path != "golang.org/x/tools/cmd/stringer" || f.Tool[0].Line != 38 {
+		t.Errorf("Tool: got %+v", f.Tool)
+	}
+	if len(f.Ignore) != 1 || f.Ignore[0].Path != "./node_modules" || f.Ignore[0].Line != 40 {
+		t.Errorf("Ignore: got %+v", f.Ignore)
+	}
+
+	if len(f.Blocks) != 4 {
+		t.Fatalf("Blocks: got %d, want 4", len(f.Blocks))
+	}
+	if b := f.Blocks[1]; b.Verb != "require" || b.Start != 17 || b.End != 21 {
+		t.Errorf("Blocks[1]: got %+v", b)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{name: "unknown_directive", in: "module a\nfoo bar\n"},
+		{name: "repeated_module", in: "module a\nmodule b\n"},
+		{name: "repeated_go", in: "go 1.21\ngo 1.22\n"},
+		{name: "bad_go_version", in: "go 1.x\n"},
+		{name: "bad_toolchain", in: "toolchain 1.21\n"},
+		{name: "unclosed_block", in: "require (\n\ta v1.0.0\n"},
+		{name: "go_block", in: "go (\n1.21\n)\n"},
+		{name: "require_missing_version", in: "require a\n"},
+		{name: "replace_no_arrow", in: "replace a b v1.0.0\n"},
+		{name: "replace_module_without_version", in: "replace a => b\n"},
+		{name: "replace_dir_with_version", in: "replace a => ./b v1.0.0\n"},
+		{name: "retract_bad_interval", in: "retract [v1.0.0 v1.1.0]\n"},
+		{name: "godebug_no_value", in: "godebug panicnil\n"},
+		{name: "unterminated_quote", in: "require \"a v1.0.0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.in)); err == nil {
+				t.Fatalf("Parse() want error, got nil")
+			}
+		})
+	}
+}
+
+func TestReplacementFor(t *testing.T) {
+	f, err := Parse([]byte("module m\nreplace a => ./wild\nreplace a v1.0.0 => b v1.0.1\n"))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	if rep := f.ReplacementFor("a", "v1.0.0"); rep == nil || rep.NewPath != "b" {
+		t.Errorf("ReplacementFor(a, v1.0.0): got %+v", rep)
+	}
+	if rep := f.ReplacementFor("a", "v2.0.0"); rep == nil || rep.NewPath != "./wild" {
+		t.Errorf("ReplacementFor(a, v2.0.0): got %+v", rep)
+	}
+	if rep := f.ReplacementFor("c", "v1.0.0"); rep != nil {
+		t.Errorf("ReplacementFor(c, v1.0.0): got %+v, want nil", rep)
+	}
+}
+
+func TestRetracted(t *testing.T) {
+	f, err := Parse([]byte(fullGoMod))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "v1.0.1", want: "broken build"},
+		{version: "v1.1.3", want: "security issue"},
+		{version: "v1.1.6"},
+		{version: "v1.0.0"},
+	}
+	for _, tt := range tests {
+		ret := f.Retracted(tt.version)
+		switch {
+		case tt.want == "" && ret != nil:
+			t.Errorf("Retracted(%s): got %+v, want nil", tt.version, ret)
+		case tt.want != "" && (ret == nil || ret.Rationale != tt.want):
+			t.Errorf("Retracted(%s): got %+v, want rationale %q", tt.version, ret, tt.want)
+		}
+	}
+}
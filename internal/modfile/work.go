@@ -0,0 +1,62 @@
+package modfile
+
+import "fmt"
+
+type WorkFile struct {
+	Go        *Go        `json:"go,omitempty"`
+	Toolchain *Toolchain `json:"toolchain,omitempty"`
+	Godebug   []*Godebug `json:"godebug,omitempty"`
+	Use       []*Use     `json:"use,omitempty"`
+	Replace   []*Replace `json:"replace,omitempty"`
+	Blocks    []*Block   `json:"-"`
+}
+
+type Use struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+}
+
+func ParseWork(data []byte) (*WorkFile, error) {
+	work := &WorkFile{}
+	blocks, err := parseLines(data, workBlockAllowed, work.add)
+	if err != nil {
+		return nil, err
+	}
+	work.Blocks = blocks
+	return work, nil
+}
+
+func workBlockAllowed(verb string) bool {
+	switch verb {
+	case "use", "replace", "godebug":
+		return true
+	}
+	return false
+}
+
+func (w *WorkFile) add(verb string, args []token, comment string, pending []string, line int) error {
+	switch verb {
+	case "use":
+		if err := checkParens(verb, args, line); err != nil {
+			return err
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("line %d: use directive malformed", line)
+		}
+		w.Use = append(w.Use, &Use{Path: args[0].text, Line: line})
+		return nil
+
+	case "go", "toolchain", "godebug", "replace":
+		shared := &File{Go: w.Go, Toolchain: w.Toolchain}
+		if err := shared.add(verb, args, comment, pending, line); err != nil {
+			return err
+		}
+		w.Go, w.Toolchain = shared.Go, shared.Toolchain
+		w.Godebug = append(w.Godebug, shared.Godebug...)
+		w.Replace = append(w.Replace, shared.Replace...)
+		return nil
+
+	default:
+		return fmt.Errorf("line %d: unknown go.work directive %q", line, verb)
+	}
+}
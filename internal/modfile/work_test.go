@@ -0,0 +1,71 @@
+package modfile
+
+import "testing"
+
+func TestParseWork(t *testing.T) {
+	in := `go 1.23
+
+toolchain go1.23.4
+
+use (
+	.
+	./tools // helper module
+	"./with space"
+)
+
+use ../shared
+
+replace example.com/a => ../a
+`
+	w, err := ParseWork([]byte(in))
+	if err != nil {
+		t.Fatalf("ParseWork() unexpected error: %v", err)
+	}
+
+	if w.Go == nil || w.Go.Version != "1.23" {
+		t.Errorf("Go: got %+v", w.Go)
+	}
+	if w.Toolchain == nil || w.Toolchain.Name != "go1.23.4" {
+		t.Errorf("Toolchain: got %+v", w.Toolchain)
+	}
+
+	wantUse := []Use{
+		{Path: ".", Line: 6},
+		{Path: "./tools", Line: 7},
+		{Path: "./with space", Line: 8},
+		{Path: "../shared", Line: 11},
+	}
+	if len(w.Use) != len(wantUse) {
+		t.Fatalf("Use: got %d entries, want %d", len(w.Use), len(wantUse))
+	}
+	for i, want := range wantUse {
+		if *w.Use[i] != want {
+			t.Errorf("Use[%d]: got %+v, want %+v", i, *w.Use[i], want)
+		}
+	}
+
+	if len(w.Replace) != 1 || w.Replace[0].NewPath != "../a" {
+		t.Errorf("Replace: got %+v", w.Replace)
+	}
+}
+
+func TestParseWorkErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{name: "module_directive", in: "module a\n"},
+		{name: "require_directive", in: "require a v1.0.0\n"},
+		{name: "use_two_args", in: "use ./a ./b\n"},
+		{name: "repeated_go", in: "go 1.22\ngo 1.23\n"},
+		{name: "go_block", in: "go (\n1.22\n)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseWork([]byte(tt.in)); err == nil {
+				t.Fatalf("ParseWork() want error, got nil")
+			}
+		})
+	}
+}
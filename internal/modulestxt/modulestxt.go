@@ -0,0 +1,128 @@
+package modulestxt
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Module struct {
+	Path       string   `json:"path"`
+	Version    string   `json:"version"`
+	NewPath    string   `json:"new_path,omitempty"`
+	NewVersion string   `json:"new_version,omitempty"`
+	Explicit   bool     `json:"explicit"`
+	GoVersion  string   `json:"go_version,omitempty"`
+	Packages   []string `json:"packages,omitempty"`
+	Line       int      `json:"line"`
+}
+
+type File struct {
+	Modules   []*Module
+	Workspace bool
+}
+
+func Parse(data []byte) (*File, error) {
+	file := &File{}
+	var current *Module
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(raw)
+		num := i + 1
+
+		switch {
+		case line == "":
+			continue
+
+		case strings.HasPrefix(line, "## "):
+			if err := file.annotate(current, line[3:], num); err != nil {
+				return nil, err
+			}
+
+		case strings.HasPrefix(line, "# "):
+			mod, err := parseModuleLine(strings.Fields(line[2:]), num)
+			if err != nil {
+				return nil, err
+			}
+			file.Modules = append(file.Modules, mod)
+			current = mod
+
+		case strings.HasPrefix(line, "#"):
+			return nil, fmt.Errorf("modules.txt line %d: malformed comment line", num)
+
+		default:
+			if current == nil {
+				return nil, fmt.Errorf("modules.txt line %d: package %s listed before any module", num, line)
+			}
+			if current.Version == "" {
+				return nil, fmt.Errorf("modules.txt line %d: package %s listed under replacement-only line", num, line)
+			}
+			current.Packages = append(current.Packages, line)
+		}
+	}
+
+	return file, nil
+}
+
+func (f *File) Module(path string) *Module {
+	for _, mod := range f.Modules {
+		if mod.Path == path && mod.Version != "" {
+			return mod
+		}
+	}
+	return nil
+}
+
+func (f *File) annotate(current *Module, text string, line int) error {
+	for _, part := range strings.Split(text, ";") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+			continue
+		case part == "workspace" && current == nil:
+			f.Workspace = true
+		case current == nil:
+			return fmt.Errorf("modules.txt line %d: annotation %q before any module", line, part)
+		case part == "explicit":
+			current.Explicit = true
+		case strings.HasPrefix(part, "go "):
+			current.GoVersion = strings.TrimSpace(part[3:])
+		default:
+			return fmt.Errorf("modules.txt line %d: unknown annotation %q", line, part)
+		}
+	}
+	return nil
+}
+
+func parseModuleLine(fields []string, line int) (*Module, error) {
+	mod := &Module{Line: line}
+
+	left, right := fields, []string(nil)
+	for i, field := range fields {
+		if field == "=>" {
+			left, right = fields[:i], fields[i+1:]
+			if len(right) == 0 || len(right) > 2 {
+				return nil, fmt.Errorf("modules.txt line %d: malformed replacement", line)
+			}
+			break
+		}
+	}
+
+	if len(left) == 0 || len(left) > 2 {
+		return nil, fmt.Errorf("modules.txt line %d: malformed module line", line)
+	}
+	if len(left) == 1 && len(right) == 0 {
+		return nil, fmt.Errorf("modules.txt line %d: module %s has no version", line, left[0])
+	}
+	mod.Path = left[0]
+	if len(left) == 2 {
+		mod.Version = left[1]
+	}
+	if len(right) > 0 {
+		mod.NewPath = right[0]
+	}
+	if len(right) == 2 {
+		mod.NewVersion = right[1]
+	}
+
+	return mod, nil
+}
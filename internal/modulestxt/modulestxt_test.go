@@ -0,0 +1,83 @@
+package modulestxt
+
+import (
+	"reflect"
+	"testing"
+)
+
+const sample = `## workspace
+# github.com/a/b v1.2.3
+## explicit; go 1.21
+github.com/a/b
+github.com/a/b/internal/x
+# golang.org/x/text v0.14.0
+## explicit
+golang.org/x/text/unicode/norm
+# github.com/c/d v0.1.0 => github.com/fork/d v0.1.1
+## explicit; go 1.18
+github.com/c/d
+# github.com/local/e v1.0.0 => ../e
+## explicit
+github.com/local/e
+# github.com/wild/f => ./f
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if !f.Workspace {
+		t.Errorf("Workspace: got false, want true")
+	}
+
+	want := []Module{
+		{Path: "github.com/a/b", Version: "v1.2.3", Explicit: true, GoVersion: "1.21",
+			Packages: []string{"github.com/a/b", "github.com/a/b/internal/x"}, Line: 2},
+		{Path: "golang.org/x/text", Version: "v0.14.0", Explicit: true,
+			Packages: []string{"golang.org/x/text/unicode/norm"}, Line: 6},
+		{Path: "github.com/c/d", Version: "v0.1.0", NewPath: "github.com/fork/d", NewVersion: "v0.1.1",
+			Explicit: true, GoVersion: "1.18", Packages: []string{"github.com/c/d"}, Line: 9},
+		{Path: "github.com/local/e", Version: "v1.0.0", NewPath: "../e", Explicit: true,
+			Packages: []string{"github.com/local/e"}, Line: 12},
+		{Path: "github.com/wild/f", NewPath: "./f", Line: 15},
+	}
+	if len(f.Modules) != len(want) {
+		t.Fatalf("Modules: got %d, want %d", len(f.Modules), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(*f.Modules[i], want[i]) {
+			t.Errorf("Modules[%d]: got %+v, want %+v", i, *f.Modules[i], want[i])
+		}
+	}
+
+	if mod := f.Module("github.com/wild/f"); mod != nil {
+		t.Errorf("Module(wild/f): got %+v, want nil", mod)
+	}
+	if mod := f.Module("golang.org/x/text"); mod == nil || mod.Version != "v0.14.0" {
+		t.Errorf("Module(x/text): got %+v", mod)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{name: "package_before_module", in: "github.com/a/b\n"},
+		{name: "annotation_before_module", in: "## explicit\n"},
+		{name: "unknown_annotation", in: "# a v1.0.0\n## bogus\n"},
+		{name: "module_without_version", in: "# a\n"},
+		{name: "package_under_replacement_only", in: "# a => ./a\na\n"},
+		{name: "bad_replacement", in: "# a v1.0.0 =>\n"},
+		{name: "bad_comment", in: "#a v1.0.0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.in)); err == nil {
+				t.Fatalf("Parse() want error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package rpmver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/reservation-v/vlang/internal/semver"
+)
+
+type RPMVersion struct {
+	Version string `json:"version"`
+	Release string `json:"release"`
+}
+
+func (v RPMVersion) String() string {
+	return v.Version + "-" + v.Release
+}
+
+func (v RPMVersion) Compare(other RPMVersion) int {
+	return CompareEVR("", v.Version, v.Release, "", other.Version, other.Release)
+}
+
+// Parse splits "version-release" as printed by String.
+func Parse(s string) (RPMVersion, error) {
+	idx := strings.LastIndex(s, "-")
+	if idx <= 0 || idx == len(s)-1 {
+		return RPMVersion{}, fmt.Errorf("%q is not version-release", s)
+	}
+	return RPMVersion{Version: s[:idx], Release: s[idx+1:]}, nil
+}
+
+// FromGo maps an upstream tag or Go module version to ALT Version/Release:
+//
+//	v1.2.3                               -> 1.2.3   alt1
+//	v1.2.0-rc.1                          -> 1.2.0   alt0.rc1
+//	v0.0.0-20191109021931-daa7c04131f5   -> 0.0.0   alt0.git20191109021931.daa7c04131f5
+//	v1.2.4-0.20200101120000-abcdefabcdef -> 1.2.4   alt0.git20200101120000.abcdefabcdef
+//	v1.3.0-rc.1.0.20210203040506-0123456789ab
+//	                                     -> 1.3.0   alt0.rc1.git20210203040506.0123456789ab
+//
+// Prereleases and pseudo-versions get an alt0 release so that the final
+// alt1 build of the same version sorts after them. Pseudo-versions keep the
+// full commit time, so two snapshots of one day sort by time rather than
+// by their commit hashes.
+func FromGo(v string) (RPMVersion, error) {
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	ver, err := semver.Parse(v)
+	if err != nil {
+		return RPMVersion{}, err
+	}
+	version := ver.Major + "." + ver.Minor + "." + ver.Patch
+
+	if semver.IsPseudo(v) {
+		pseudo, pseudoErr := semver.ParsePseudo(v)
+		if pseudoErr != nil {
+			return RPMVersion{}, pseudoErr
+		}
+		release := "alt0."
+		if pseudo.Form == 2 {
+			base, _ := semver.Parse(pseudo.Base)
+			release += prereleaseTag(base.Prerelease) + "."
+		}
+		release += "git" + pseudo.Time.Format("20060102150405") + "." + pseudo.Revision
+		return RPMVersion{Version: version, Release: release}, nil
+	}
+
+	if ver.Prerelease != "" {
+		return RPMVersion{Version: version, Release: "alt0." + prereleaseTag(ver.Prerelease)}, nil
+	}
+
+	return RPMVersion{Version: version, Release: "alt1"}, nil
+}
+
+// ProvidesVersion maps a Go module version to a single RPM version for a
+// Provides: line, which has no release to carry prerelease and pseudo-version
+// details. They follow a '~' so that they sort before the final version:
+//
+//	v1.2.3                               -> 1.2.3
+//	v1.2.0-rc.1                          -> 1.2.0~rc1
+//	v1.2.4-0.20200101120000-abcdefabcdef -> 1.2.4~git20200101120000.abcdefabcdef
+func ProvidesVersion(v string) (string, error) {
+	mapped, err := FromGo(v)
+	if err != nil {
+		return "", err
+	}
+	tag, ok := strings.CutPrefix(mapped.Release, "alt0.")
+	if !ok {
+		return mapped.Version, nil
+	}
+	return mapped.Version + "~" + tag, nil
+}
+
+// prereleaseTag turns semver prerelease identifiers into a release-safe tag:
+// a numeric identifier is glued to a preceding alphabetic one ("rc.1" ->
+// "rc1"), other identifiers are dot-separated and '-' becomes '.'.
+func prereleaseTag(pre string) string {
+	idents := strings.Split(pre, ".")
+
+	var b strings.Builder
+	for i, ident := range idents {
+		ident = strings.ReplaceAll(ident, "-", ".")
+		if i > 0 && !(isNumeric(ident) && !isNumeric(idents[i-1])) {
+			b.WriteByte('.')
+		}
+		b.WriteString(ident)
+	}
+	return b.String()
+}
+
+func isNumeric(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(r rune) bool { return !isDigit(r) }) < 0
+}
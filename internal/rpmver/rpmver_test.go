@@ -0,0 +1,174 @@
+package rpmver
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0.1", "2.0", 1},
+		{"2.0.1a", "2.0.1", 1},
+		{"5.5p1", "5.5p2", -1},
+		{"5.5p10", "5.5p1", 1},
+		{"10xyz", "10.1xyz", -1},
+		{"xyz10", "xyz10.1", -1},
+		{"xyz.4", "8", -1},
+		{"1b.fc17", "1.fc17", -1},
+		{"1.0010", "1.9", 1},
+		{"1.05", "1.5", 0},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0^", "1.0", 1},
+		{"1.0^git1", "1.01", -1},
+		{"1.0^git1", "1.0~rc1", 1},
+		{"alt0.rc1", "alt1", -1},
+		{"alt0.rc1", "alt0.rc1.git20210203.0123", -1},
+		{"alt0.git20200101.abc", "alt1", -1},
+		{"alt1", "alt2", -1},
+		{"alt10", "alt9", 1},
+		{"2_0", "2.0", 0},
+		{"a+", "a_", 0},
+		{"+", "_", 0},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) got %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) got %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestCompareEVR(t *testing.T) {
+	if CompareEVR("", "2.0", "alt1", "1", "1.0", "alt1") != -1 {
+		t.Errorf("epoch 1 must win over higher version")
+	}
+	if CompareEVR("0", "1.0", "alt2", "", "1.0", "alt1") != 1 {
+		t.Errorf("release alt2 must win over alt1")
+	}
+}
+
+func TestFromGo(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    RPMVersion
+		wantErr bool
+	}{
+		{in: "v1.2.3", want: RPMVersion{"1.2.3", "alt1"}},
+		{in: "1.2.3", want: RPMVersion{"1.2.3", "alt1"}},
+		{in: "v2.0.0+incompatible", want: RPMVersion{"2.0.0", "alt1"}},
+		{in: "v1.2", want: RPMVersion{"1.2.0", "alt1"}},
+		{in: "v1.2.0-rc.1", want: RPMVersion{"1.2.0", "alt0.rc1"}},
+		{in: "v1.2.0-beta", want: RPMVersion{"1.2.0", "alt0.beta"}},
+		{in: "v1.2.0-alpha.1.x-y", want: RPMVersion{"1.2.0", "alt0.alpha1.x.y"}},
+		{in: "v0.0.0-20191109021931-daa7c04131f5", want: RPMVersion{"0.0.0", "alt0.git20191109021931.daa7c04131f5"}},
+		{in: "v1.2.4-0.20200101120000-abcdefabcdef", want: RPMVersion{"1.2.4", "alt0.git20200101120000.abcdefabcdef"}},
+		{
+			in:   "v1.3.0-rc.1.0.20210203040506-0123456789ab",
+			want: RPMVersion{"1.3.0", "alt0.rc1.git20210203040506.0123456789ab"},
+		},
+		{in: "release-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := FromGo(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("FromGo() want error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromGo() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("FromGo() got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvidesVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "v1.2.3", want: "1.2.3"},
+		{in: "v2.0.0+incompatible", want: "2.0.0"},
+		{in: "v1.2.0-rc.1", want: "1.2.0~rc1"},
+		{in: "v0.0.0-20191109021931-daa7c04131f5", want: "0.0.0~git20191109021931.daa7c04131f5"},
+		{in: "v1.3.0-rc.1.0.20210203040506-0123456789ab", want: "1.3.0~rc1.git20210203040506.0123456789ab"},
+	}
+
+	for _, tt := range tests {
+		got, err := ProvidesVersion(tt.in)
+		if err != nil {
+			t.Fatalf("ProvidesVersion(%q) error: %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("ProvidesVersion(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if _, err := ProvidesVersion("release-1"); err == nil {
+		t.Errorf("ProvidesVersion(release-1) want error")
+	}
+
+	prev, _ := ProvidesVersion("v1.2.4-0.20200101120000-abcdefabcdef")
+	for _, v := range []string{"v1.2.4-0.20200101230000-0123456789ab", "v1.2.4-rc.1", "v1.2.4"} {
+		next, _ := ProvidesVersion(v)
+		if Compare(prev, next) >= 0 {
+			t.Errorf("%s must sort before %s", prev, next)
+		}
+		prev = next
+	}
+}
+
+func TestFromGoPreservesOrder(t *testing.T) {
+	ordered := []string{
+		"v1.2.3",
+		"v1.2.4-0.20200101120000-abcdefabcdef",
+		// Same day, later commit with a hash that sorts lower.
+		"v1.2.4-0.20200101230000-0123456789ab",
+		"v1.3.0-rc.1",
+		"v1.3.0-rc.1.0.20210203040506-0123456789ab",
+		"v1.3.0-rc.2",
+		"v1.3.0",
+		"v1.10.0",
+	}
+
+	for i := 1; i < len(ordered); i++ {
+		prev, err := FromGo(ordered[i-1])
+		if err != nil {
+			t.Fatalf("FromGo(%s): %v", ordered[i-1], err)
+		}
+		next, err := FromGo(ordered[i])
+		if err != nil {
+			t.Fatalf("FromGo(%s): %v", ordered[i], err)
+		}
+		if prev.Compare(next) >= 0 {
+			t.Errorf("%s (%s) must sort before %s (%s)", ordered[i-1], prev, ordered[i], next)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	got, err := Parse("1.2.0-alt0.rc1")
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if got != (RPMVersion{"1.2.0", "alt0.rc1"}) {
+		t.Fatalf("Parse() got %+v", got)
+	}
+	for _, bad := range []string{"1.2.0", "-alt1", "1.2.0-"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) want error", bad)
+		}
+	}
+}
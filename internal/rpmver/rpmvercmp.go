@@ -0,0 +1,126 @@
+package rpmver
+
+import "strings"
+
+// Compare is a port of rpm's rpmvercmp: it splits both strings into
+// alternating numeric and alphabetic segments and compares them pairwise.
+// '~' sorts before anything, even the end of the string; '^' sorts after the
+// end of the string but before any other segment.
+func Compare(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	for len(a) > 0 || len(b) > 0 {
+		a = strings.TrimLeftFunc(a, isSeparator)
+		b = strings.TrimLeftFunc(b, isSeparator)
+
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			if a == "" {
+				return -1
+			}
+			if b == "" {
+				return 1
+			}
+			if !strings.HasPrefix(a, "^") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "^") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		isNum := isDigit(rune(a[0]))
+		segment := isAlpha
+		if isNum {
+			segment = isDigit
+		}
+		segA, restA := cutSegment(a, segment)
+		segB, restB := cutSegment(b, segment)
+		a, b = restA, restB
+
+		if segB == "" {
+			if isNum {
+				return 1
+			}
+			return -1
+		}
+
+		if isNum {
+			segA = strings.TrimLeft(segA, "0")
+			segB = strings.TrimLeft(segB, "0")
+			if len(segA) != len(segB) {
+				if len(segA) > len(segB) {
+					return 1
+				}
+				return -1
+			}
+		}
+		if c := strings.Compare(segA, segB); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	}
+	return 1
+}
+
+// CompareEVR compares epoch, version and release in that order. An empty
+// epoch is treated as 0.
+func CompareEVR(epochA, versionA, releaseA, epochB, versionB, releaseB string) int {
+	if epochA == "" {
+		epochA = "0"
+	}
+	if epochB == "" {
+		epochB = "0"
+	}
+	if c := Compare(epochA, epochB); c != 0 {
+		return c
+	}
+	if c := Compare(versionA, versionB); c != 0 {
+		return c
+	}
+	return Compare(releaseA, releaseB)
+}
+
+func cutSegment(s string, in func(rune) bool) (segment, rest string) {
+	end := strings.IndexFunc(s, func(r rune) bool { return !in(r) })
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+func isSeparator(r rune) bool {
+	return !isDigit(r) && !isAlpha(r) && r != '~' && r != '^'
+}
+
+func isDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+func isAlpha(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
@@ -0,0 +1,79 @@
+package semver
+
+import (
+	"cmp"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var goVersionRE = regexp.MustCompile(`^([0-9]+)(?:\.([0-9]+))?(?:\.([0-9]+)|(alpha|beta|rc)([0-9]+))?$`)
+
+type goVersion struct {
+	major, minor, patch int
+	kind                int
+	pre                 int
+}
+
+const (
+	goKindLanguage = iota
+	goKindAlpha
+	goKindBeta
+	goKindRC
+	goKindRelease
+)
+
+// CompareGo orders Go toolchain versions as used by the go and toolchain
+// directives ("1.21", "1.21rc1", "1.21.0", optionally prefixed with "go"):
+// a language version sorts before its prereleases, which sort before its
+// patch releases. Invalid versions sort first.
+func CompareGo(a, b string) int {
+	va, okA := parseGo(a)
+	vb, okB := parseGo(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+
+	return cmp.Or(
+		cmp.Compare(va.major, vb.major),
+		cmp.Compare(va.minor, vb.minor),
+		cmp.Compare(va.kind, vb.kind),
+		cmp.Compare(va.patch, vb.patch),
+		cmp.Compare(va.pre, vb.pre),
+	)
+}
+
+func IsValidGo(v string) bool {
+	_, ok := parseGo(v)
+	return ok
+}
+
+func parseGo(v string) (goVersion, bool) {
+	m := goVersionRE.FindStringSubmatch(strings.TrimPrefix(v, "go"))
+	if m == nil {
+		return goVersion{}, false
+	}
+
+	var gv goVersion
+	gv.major, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		gv.minor, _ = strconv.Atoi(m[2])
+	}
+	switch {
+	case m[3] != "":
+		gv.kind = goKindRelease
+		gv.patch, _ = strconv.Atoi(m[3])
+	case m[4] != "":
+		gv.kind = map[string]int{"alpha": goKindAlpha, "beta": goKindBeta, "rc": goKindRC}[m[4]]
+		gv.pre, _ = strconv.Atoi(m[5])
+	default:
+		gv.kind = goKindLanguage
+	}
+
+	return gv, true
+}
@@ -0,0 +1,98 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Kind string
+
+const (
+	KindRelease    Kind = "release"
+	KindPrerelease Kind = "prerelease"
+	KindPseudo     Kind = "pseudo"
+)
+
+const pseudoTimeLayout = "20060102150405"
+
+var pseudoRE = regexp.MustCompile(`^v[0-9]+\.(0\.0-|[0-9]+\.[0-9]+-([^+]*\.)?0\.)[0-9]{14}-[A-Za-z0-9]+(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// Pseudo describes a pseudo-version. Form is 1 for vX.0.0-ts-rev (no base
+// tag), 2 for vX.Y.Z-pre.0.ts-rev (base is the prerelease vX.Y.Z-pre) and 3
+// for vX.Y.(Z+1)-0.ts-rev (base is the release vX.Y.Z).
+type Pseudo struct {
+	Form     int
+	Base     string
+	Time     time.Time
+	Revision string
+}
+
+func IsPseudo(v string) bool {
+	return pseudoRE.MatchString(v) && IsValid(v)
+}
+
+func Classify(v string) (Kind, error) {
+	ver, err := Parse(v)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case IsPseudo(v):
+		return KindPseudo, nil
+	case ver.Prerelease != "":
+		return KindPrerelease, nil
+	}
+	return KindRelease, nil
+}
+
+func ParsePseudo(v string) (Pseudo, error) {
+	if !IsPseudo(v) {
+		return Pseudo{}, fmt.Errorf("%q is not a pseudo-version", v)
+	}
+	ver, _ := Parse(v)
+
+	pre := ver.Prerelease
+	revSep := strings.LastIndex(pre, "-")
+	revision := pre[revSep+1:]
+	rest := pre[:revSep]
+
+	timeSep := strings.LastIndex(rest, ".")
+	stamp := rest[timeSep+1:]
+	prefix := ""
+	if timeSep >= 0 {
+		prefix = rest[:timeSep]
+	}
+
+	ts, timeErr := time.Parse(pseudoTimeLayout, stamp)
+	if timeErr != nil {
+		return Pseudo{}, fmt.Errorf("pseudo-version %q has invalid timestamp: %w", v, timeErr)
+	}
+
+	p := Pseudo{Time: ts.UTC(), Revision: revision}
+	switch {
+	case timeSep < 0:
+		p.Form = 1
+	case prefix == "0":
+		p.Form = 3
+		patch, _ := strconv.ParseUint(ver.Patch, 10, 64)
+		if patch == 0 {
+			return Pseudo{}, fmt.Errorf("pseudo-version %q has release base with patch 0", v)
+		}
+		p.Base = fmt.Sprintf("v%s.%s.%d", ver.Major, ver.Minor, patch-1)
+	default:
+		p.Form = 2
+		base, ok := strings.CutSuffix(prefix, ".0")
+		if !ok {
+			return Pseudo{}, fmt.Errorf("pseudo-version %q has malformed prerelease base", v)
+		}
+		p.Base = fmt.Sprintf("v%s.%s.%s-%s", ver.Major, ver.Minor, ver.Patch, base)
+	}
+	if p.Base != "" && ver.Incompatible() {
+		p.Base += "+incompatible"
+	}
+
+	return p, nil
+}
@@ -0,0 +1,224 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Version struct {
+	Major      string
+	Minor      string
+	Patch      string
+	Prerelease string
+	Build      string
+	// Short is set for the "v1" and "v1.2" shorthands accepted by the go
+	// command; the missing parts read as zero.
+	Short bool
+}
+
+func Parse(v string) (Version, error) {
+	rest, ok := strings.CutPrefix(v, "v")
+	if !ok {
+		return Version{}, fmt.Errorf("version %q must start with v", v)
+	}
+
+	var ver Version
+	if core, build, found := strings.Cut(rest, "+"); found {
+		if !validIdents(build, false) {
+			return Version{}, fmt.Errorf("version %q has invalid build metadata", v)
+		}
+		rest, ver.Build = core, build
+	}
+	if core, pre, found := strings.Cut(rest, "-"); found {
+		if !validIdents(pre, true) {
+			return Version{}, fmt.Errorf("version %q has invalid prerelease", v)
+		}
+		rest, ver.Prerelease = core, pre
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("version %q has too many components", v)
+	}
+	for _, part := range parts {
+		if !isNum(part) {
+			return Version{}, fmt.Errorf("version %q has invalid number %q", v, part)
+		}
+	}
+
+	ver.Major = parts[0]
+	ver.Minor, ver.Patch = "0", "0"
+	if len(parts) > 1 {
+		ver.Minor = parts[1]
+	}
+	if len(parts) > 2 {
+		ver.Patch = parts[2]
+	}
+	if len(parts) < 3 {
+		if ver.Prerelease != "" || ver.Build != "" {
+			return Version{}, fmt.Errorf("shorthand version %q cannot have prerelease or build", v)
+		}
+		ver.Short = true
+	}
+
+	return ver, nil
+}
+
+func IsValid(v string) bool {
+	_, err := Parse(v)
+	return err == nil
+}
+
+// String returns the canonical form, with shorthands expanded and build
+// metadata dropped except for +incompatible.
+func (v Version) String() string {
+	s := "v" + v.Major + "." + v.Minor + "." + v.Patch
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Incompatible() {
+		s += "+incompatible"
+	}
+	return s
+}
+
+func (v Version) Incompatible() bool {
+	return v.Build == "incompatible"
+}
+
+func Canonical(v string) string {
+	ver, err := Parse(v)
+	if err != nil {
+		return ""
+	}
+	return ver.String()
+}
+
+func Major(v string) string {
+	ver, err := Parse(v)
+	if err != nil {
+		return ""
+	}
+	return "v" + ver.Major
+}
+
+// Compare orders versions by semver precedence; build metadata is ignored.
+// Invalid versions sort before all valid ones and are equal to each other.
+func Compare(a, b string) int {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return 0
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+
+	if c := compareNum(va.Major, vb.Major); c != 0 {
+		return c
+	}
+	if c := compareNum(va.Minor, vb.Minor); c != 0 {
+		return c
+	}
+	if c := compareNum(va.Patch, vb.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(va.Prerelease, vb.Prerelease)
+}
+
+func Max(a, b string) string {
+	if Compare(a, b) < 0 {
+		return b
+	}
+	return a
+}
+
+// InInterval reports whether v lies in [low, high], the form used by
+// retract directives.
+func InInterval(v, low, high string) bool {
+	return Compare(low, v) <= 0 && Compare(v, high) <= 0
+}
+
+func compareNum(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	ia, ib := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(ia) && i < len(ib); i++ {
+		if c := compareIdent(ia[i], ib[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(ia) < len(ib):
+		return -1
+	case len(ia) > len(ib):
+		return 1
+	}
+	return 0
+}
+
+func compareIdent(a, b string) int {
+	numA, numB := isNum(a), isNum(b)
+	switch {
+	case numA && numB:
+		return compareNum(a, b)
+	case numA:
+		return -1
+	case numB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func isNum(s string) bool {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func validIdents(s string, prerelease bool) bool {
+	for _, ident := range strings.Split(s, ".") {
+		if ident == "" {
+			return false
+		}
+		allDigits := true
+		for _, r := range ident {
+			switch {
+			case '0' <= r && r <= '9':
+			case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', r == '-':
+				allDigits = false
+			default:
+				return false
+			}
+		}
+		if prerelease && allDigits && len(ident) > 1 && ident[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,214 @@
+package semver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in        string
+		canonical string
+		wantErr   bool
+	}{
+		{in: "v1.2.3", canonical: "v1.2.3"},
+		{in: "v1", canonical: "v1.0.0"},
+		{in: "v1.2", canonical: "v1.2.0"},
+		{in: "v1.2.3-rc.1", canonical: "v1.2.3-rc.1"},
+		{in: "v1.2.3+meta", canonical: "v1.2.3"},
+		{in: "v2.0.0+incompatible", canonical: "v2.0.0+incompatible"},
+		{in: "1.2.3", wantErr: true},
+		{in: "v1.2.3.4", wantErr: true},
+		{in: "v01.2.3", wantErr: true},
+		{in: "v1.2.3-01", wantErr: true},
+		{in: "v1.2.3-", wantErr: true},
+		{in: "v1.2-pre", wantErr: true},
+		{in: "v1.2.3-a..b", wantErr: true},
+		{in: "v1.2.3+bad_meta", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse() want error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if got.String() != tt.canonical {
+				t.Fatalf("String() got %q, want %q", got.String(), tt.canonical)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []string{
+		"bad",
+		"v0.0.0-20191109021931-daa7c04131f5",
+		"v0.0.1",
+		"v1.0.0-alpha",
+		"v1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0-rc.1",
+		"v1.0.0",
+		"v1.2.3",
+		"v1.2.4-0.20200101000000-abcdefabcdef",
+		"v1.2.4",
+		"v1.10.0",
+		"v2.0.0+incompatible",
+		"v10.0.0",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := Compare(ordered[i], ordered[j]); got != want {
+				t.Errorf("Compare(%q, %q) got %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+
+	if Compare("v1.2.3+a", "v1.2.3+b") != 0 {
+		t.Errorf("Compare ignores build metadata")
+	}
+	if Compare("v1", "v1.0.0") != 0 {
+		t.Errorf("Compare(v1, v1.0.0) want 0")
+	}
+}
+
+func TestInInterval(t *testing.T) {
+	if !InInterval("v1.1.3", "v1.1.0", "v1.1.5") {
+		t.Errorf("v1.1.3 in [v1.1.0, v1.1.5]: got false")
+	}
+	if InInterval("v1.2.0", "v1.1.0", "v1.1.5") {
+		t.Errorf("v1.2.0 in [v1.1.0, v1.1.5]: got true")
+	}
+	if !InInterval("v1.0.1", "v1.0.1", "v1.0.1") {
+		t.Errorf("v1.0.1 in [v1.0.1, v1.0.1]: got false")
+	}
+}
+
+func TestMajorAndMax(t *testing.T) {
+	if got := Major("v2.3.4+incompatible"); got != "v2" {
+		t.Errorf("Major() got %q", got)
+	}
+	if got := Major("bad"); got != "" {
+		t.Errorf("Major(bad) got %q", got)
+	}
+	if got := Max("v1.2.0", "v1.10.0"); got != "v1.10.0" {
+		t.Errorf("Max() got %q", got)
+	}
+}
+
+func TestClassifyAndParsePseudo(t *testing.T) {
+	tests := []struct {
+		in       string
+		kind     Kind
+		form     int
+		base     string
+		time     string
+		revision string
+	}{
+		{in: "v1.2.3", kind: KindRelease},
+		{in: "v2.0.0+incompatible", kind: KindRelease},
+		{in: "v1.2.3-rc.1", kind: KindPrerelease},
+		{
+			in: "v0.0.0-20191109021931-daa7c04131f5", kind: KindPseudo,
+			form: 1, time: "2019-11-09T02:19:31Z", revision: "daa7c04131f5",
+		},
+		{
+			in: "v1.2.4-0.20200101120000-abcdefabcdef", kind: KindPseudo,
+			form: 3, base: "v1.2.3", time: "2020-01-01T12:00:00Z", revision: "abcdefabcdef",
+		},
+		{
+			in: "v1.3.0-rc.1.0.20210203040506-0123456789ab", kind: KindPseudo,
+			form: 2, base: "v1.3.0-rc.1", time: "2021-02-03T04:05:06Z", revision: "0123456789ab",
+		},
+		{
+			in: "v3.0.1-0.20220101000000-abcdefabcdef+incompatible", kind: KindPseudo,
+			form: 3, base: "v3.0.0+incompatible", time: "2022-01-01T00:00:00Z", revision: "abcdefabcdef",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			kind, err := Classify(tt.in)
+			if err != nil {
+				t.Fatalf("Classify() unexpected error: %v", err)
+			}
+			if kind != tt.kind {
+				t.Fatalf("Classify() got %q, want %q", kind, tt.kind)
+			}
+			if kind != KindPseudo {
+				if _, err := ParsePseudo(tt.in); err == nil {
+					t.Fatalf("ParsePseudo() want error for %s", kind)
+				}
+				return
+			}
+
+			p, err := ParsePseudo(tt.in)
+			if err != nil {
+				t.Fatalf("ParsePseudo() unexpected error: %v", err)
+			}
+			if p.Form != tt.form || p.Base != tt.base || p.Revision != tt.revision {
+				t.Fatalf("ParsePseudo() got %+v", p)
+			}
+			if got := p.Time.Format(time.RFC3339); got != tt.time {
+				t.Fatalf("Time: got %q, want %q", got, tt.time)
+			}
+		})
+	}
+
+	if _, err := Classify("1.0.0"); err == nil {
+		t.Errorf("Classify(1.0.0) want error")
+	}
+	if _, err := ParsePseudo("v1.2.0-0.20200101120000-abcdefabcdef"); err == nil {
+		t.Errorf("ParsePseudo() with patch 0 release base want error")
+	}
+}
+
+func TestCompareGo(t *testing.T) {
+	ordered := []string{
+		"bad",
+		"1.20.14",
+		"1.21",
+		"1.21beta1",
+		"1.21rc1",
+		"1.21rc2",
+		"1.21.0",
+		"go1.21.1",
+		"1.22",
+		"2",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := CompareGo(ordered[i], ordered[j]); got != want {
+				t.Errorf("CompareGo(%q, %q) got %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+
+	if !IsValidGo("go1.24.2") || IsValidGo("1.x") {
+		t.Errorf("IsValidGo() misclassified versions")
+	}
+}
@@ -0,0 +1,96 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/reservation-v/vlang/internal/inspect"
+)
+
+var buildRequiresRE = regexp.MustCompile(`^BuildRequires(\([^)]*\))?:\s*(.*)$`)
+
+// checkCgoRequires warns about C libraries used through cgo, by the module
+// or its vendored packages, that .gear/<name>.spec does not BuildRequire.
+// Without a spec there is nothing to compare yet.
+func checkCgoRequires(dir, modulePath, name string) []Issue {
+	if modulePath == "" || name == "" {
+		return nil
+	}
+	specPath := filepath.Join(dir, ".gear", name+".spec")
+	spec, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil
+	}
+
+	packages, err := inspect.ScanCgo(dir, modulePath, true)
+	if err != nil {
+		return []Issue{cgoScanIssue(dir, err)}
+	}
+	vendored, err := inspect.ScanCgo(filepath.Join(dir, "vendor"), "", false)
+	if err != nil {
+		return []Issue{cgoScanIssue(filepath.Join(dir, "vendor"), err)}
+	}
+	packages = append(packages, vendored...)
+
+	have := specBuildRequires(spec)
+	var issues []Issue
+	for _, pkg := range packages {
+		for _, want := range pkg.PkgConfig {
+			if !have["pkgconfig("+want+")"] {
+				issues = append(issues, cgoIssue(specPath, pkg.ImportPath, "pkgconfig("+want+")"))
+			}
+		}
+		for _, lib := range pkg.Libs {
+			if !have[inspect.LibDevel(lib)] && !have[lib+"-devel"] && !have["pkgconfig("+lib+")"] {
+				issues = append(issues, cgoIssue(specPath, pkg.ImportPath, inspect.LibDevel(lib)))
+			}
+		}
+	}
+
+	return issues
+}
+
+// cgoScanIssue fails the check: without the scan the C libraries the
+// build needs are unknown.
+func cgoScanIssue(dir string, err error) Issue {
+	return Issue{
+		Severity: SeverityErr,
+		Code:     "CGO_SCAN_FAILED",
+		Message:  fmt.Sprintf("cannot scan for cgo packages: %v", err),
+		Path:     dir,
+	}
+}
+
+func cgoIssue(specPath, importPath, require string) Issue {
+	return Issue{
+		Severity: SeverityWarn,
+		Code:     "CGO_BUILDREQUIRES_MISSING",
+		Message:  fmt.Sprintf("package %s uses cgo; spec has no BuildRequires: %s", importPath, require),
+		Path:     specPath,
+	}
+}
+
+// specBuildRequires collects the package names of the BuildRequires tags
+// of spec, without version constraints.
+func specBuildRequires(spec []byte) map[string]bool {
+	names := make(map[string]bool)
+	for _, line := range strings.Split(string(spec), "\n") {
+		m := buildRequiresRE.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		fields := strings.FieldsFunc(m[2], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		for i := 0; i < len(fields); i++ {
+			switch fields[i] {
+			case "<", "<=", "=", ">=", ">":
+				i++ // skip the version
+				continue
+			}
+			names[fields[i]] = true
+		}
+	}
+	return names
+}
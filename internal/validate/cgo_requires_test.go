@@ -0,0 +1,46 @@
+package validate
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckCgoRequires(t *testing.T) {
+	dir := t.TempDir()
+	writeGoMod(t, dir, "example.com/app")
+	writeFile(t, filepath.Join(dir, "db", "db.go"), "package db\n\n// #cgo pkg-config: sqlite3\n// #cgo LDFLAGS: -lssl -lz\nimport \"C\"\n")
+	writeFile(t, filepath.Join(dir, "vendor", "example.com", "usb", "usb.go"), "package usb\n\n// #cgo pkg-config: libusb-1.0\nimport \"C\"\n")
+
+	if issues := checkCgoRequires(dir, "example.com/app", "app"); len(issues) != 0 {
+		t.Fatalf("checkCgoRequires() without spec: got %+v", issues)
+	}
+
+	writeFile(t, filepath.Join(dir, ".gear", "app.spec"),
+		"Name: app\nBuildRequires(pre): rpm-build-golang\nBuildRequires: golang >= 1.22, pkgconfig(sqlite3)\nBuildRequires: zlib-devel\n")
+
+	issues := checkCgoRequires(dir, "example.com/app", "app")
+	want := []string{
+		"package example.com/app/db uses cgo; spec has no BuildRequires: libssl-devel",
+		"package example.com/usb uses cgo; spec has no BuildRequires: pkgconfig(libusb-1.0)",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("checkCgoRequires(): got %+v, want %d issues", issues, len(want))
+	}
+	for i, issue := range issues {
+		if issue.Code != "CGO_BUILDREQUIRES_MISSING" || issue.Severity != SeverityWarn || issue.Message != want[i] {
+			t.Errorf("issue %d: got %+v, want message %q", i, issue, want[i])
+		}
+	}
+}
+
+func TestCheckCgoRequiresScanFailed(t *testing.T) {
+	dir := t.TempDir()
+	writeGoMod(t, dir, "example.com/app")
+	writeFile(t, filepath.Join(dir, ".gear", "app.spec"), "Name: app\n")
+	writeFile(t, filepath.Join(dir, "db", "db.go"), "package db\n\nimport (\n")
+
+	issues := checkCgoRequires(dir, "example.com/app", "app")
+	if len(issues) != 1 || issues[0].Code != "CGO_SCAN_FAILED" || issues[0].Severity != SeverityErr {
+		t.Fatalf("checkCgoRequires() with an unparsable file: got %+v", issues)
+	}
+}
@@ -0,0 +1,35 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/reservation-v/vlang/internal/diff"
+)
+
+// checkGearConflicts reports generated gear files left with conflict
+// markers by a three-way merge during bootstrap.
+func checkGearConflicts(dir, name string) []Issue {
+	paths := []string{filepath.Join(dir, ".gear", "rules")}
+	if name != "" {
+		paths = append(paths, filepath.Join(dir, ".gear", name+".spec"))
+	}
+
+	var issues []Issue
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if diff.HasConflict(data) {
+			issues = append(issues, Issue{
+				Severity: SeverityErr,
+				Code:     "GEAR_MERGE_CONFLICT",
+				Message:  "unresolved merge conflict between local edits and regenerated content",
+				Path:     path,
+			})
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,32 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckGearConflicts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".gear"), 0o755); err != nil {
+		t.Fatalf("mkdir .gear: %v", err)
+	}
+	writeFile(t, filepath.Join(dir, ".gear", "rules"), "spec: .gear/app.spec\n")
+	writeFile(t, filepath.Join(dir, ".gear", "app.spec"),
+		"Name: app\n<<<<<<< current\nVersion: 1.0.1\n||||||| previously generated\nVersion: 1.0\n=======\nVersion: 1.1\n>>>>>>> generated\n")
+
+	issues := checkGearConflicts(dir, "app")
+	if len(issues) != 1 {
+		t.Fatalf("checkGearConflicts(): got %+v, want 1 issue", issues)
+	}
+	if issues[0].Code != "GEAR_MERGE_CONFLICT" || issues[0].Severity != SeverityErr {
+		t.Fatalf("issue: got %+v", issues[0])
+	}
+	if issues[0].Path != filepath.Join(dir, ".gear", "app.spec") {
+		t.Fatalf("issue path: got %q", issues[0].Path)
+	}
+
+	if issues := checkGearConflicts(t.TempDir(), "app"); len(issues) != 0 {
+		t.Fatalf("checkGearConflicts() without .gear: got %+v", issues)
+	}
+}
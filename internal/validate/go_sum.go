@@ -0,0 +1,157 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/reservation-v/vlang/internal/gosum"
+	"github.com/reservation-v/vlang/internal/modfile"
+	"github.com/reservation-v/vlang/internal/modulestxt"
+)
+
+type sumTarget struct {
+	path    string
+	version string
+	direct  bool
+}
+
+func checkGoSum(dir string, goModData []byte) []Issue {
+	if goModData == nil {
+		return nil
+	}
+
+	goModPath := filepath.Join(dir, "go.mod")
+	modFile, parseErr := modfile.Parse(goModData)
+	if parseErr != nil {
+		return []Issue{{
+			Severity: SeverityErr,
+			Code:     "GO_MOD_MALFORMED",
+			Message:  parseErr.Error(),
+			Path:     goModPath,
+		}}
+	}
+
+	targets, issue := sumTargets(dir, modFile)
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	goSumPath := filepath.Join(dir, "go.sum")
+	data, readErr := os.ReadFile(goSumPath)
+	if os.IsNotExist(readErr) {
+		return []Issue{{
+			Severity: SeverityErr,
+			Code:     "GO_SUM_MISSING",
+			Message:  "go.sum file is missing but go.mod has requirements",
+			Path:     goSumPath,
+		}}
+	}
+	if readErr != nil {
+		return []Issue{{
+			Severity: SeverityErr,
+			Code:     "GO_SUM_READ_FAILED",
+			Message:  "go.sum file cannot be read",
+			Path:     goSumPath,
+		}}
+	}
+
+	sumFile, sumErr := gosum.Parse(data)
+	if sumErr != nil {
+		return []Issue{{
+			Severity: SeverityErr,
+			Code:     "GO_SUM_MALFORMED",
+			Message:  sumErr.Error(),
+			Path:     goSumPath,
+		}}
+	}
+
+	var issues []Issue
+	for _, target := range targets {
+		if !sumFile.Has(target.path, target.version, true) {
+			issues = append(issues, Issue{
+				Severity: SeverityErr,
+				Code:     "GO_SUM_ENTRY_MISSING",
+				Message:  fmt.Sprintf("no go.mod hash for %s@%s", target.path, target.version),
+				Path:     goSumPath,
+			})
+		}
+		if !sumFile.Has(target.path, target.version, false) {
+			severity := SeverityWarn
+			if target.direct {
+				severity = SeverityErr
+			}
+			issues = append(issues, Issue{
+				Severity: severity,
+				Code:     "GO_SUM_ENTRY_MISSING",
+				Message:  fmt.Sprintf("no module hash for %s@%s", target.path, target.version),
+				Path:     goSumPath,
+			})
+		}
+	}
+
+	return issues
+}
+
+// sumTargets lists the module versions go.sum must cover: every require in
+// go.mod plus the transitive set from vendor/modules.txt, after replacements.
+// Directory replacements have no checksum and are skipped.
+func sumTargets(dir string, modFile *modfile.File) ([]sumTarget, *Issue) {
+	var targets []sumTarget
+	seen := make(map[string]int)
+
+	add := func(path, version string, direct bool) {
+		if rep := modFile.ReplacementFor(path, version); rep != nil {
+			if rep.NewVersion == "" {
+				return
+			}
+			path, version = rep.NewPath, rep.NewVersion
+		}
+		key := path + "@" + version
+		if idx, ok := seen[key]; ok {
+			targets[idx].direct = targets[idx].direct || direct
+			return
+		}
+		seen[key] = len(targets)
+		targets = append(targets, sumTarget{path: path, version: version, direct: direct})
+	}
+
+	for _, req := range modFile.Require {
+		add(req.Path, req.Version, !req.Indirect)
+	}
+
+	modulesTxtPath := filepath.Join(dir, "vendor", "modules.txt")
+	data, readErr := os.ReadFile(modulesTxtPath)
+	if os.IsNotExist(readErr) {
+		return targets, nil
+	}
+	if readErr != nil {
+		return nil, &Issue{
+			Severity: SeverityErr,
+			Code:     "MODULES_TXT_READ_FAILED",
+			Message:  "vendor/modules.txt cannot be read",
+			Path:     modulesTxtPath,
+		}
+	}
+
+	vendored, parseErr := modulestxt.Parse(data)
+	if parseErr != nil {
+		return nil, &Issue{
+			Severity: SeverityErr,
+			Code:     "MODULES_TXT_MALFORMED",
+			Message:  parseErr.Error(),
+			Path:     modulesTxtPath,
+		}
+	}
+	for _, mod := range vendored.Modules {
+		if mod.Version == "" {
+			continue
+		}
+		add(mod.Path, mod.Version, false)
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,121 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+const sumGoMod = `module example.com/app
+
+go 1.25
+
+require (
+	example.com/direct v1.0.0
+	example.com/indirect v1.1.0 // indirect
+	example.com/local v0.0.0
+	example.com/forked v1.0.0
+)
+
+replace example.com/local => ../local
+
+replace example.com/forked => example.com/fork v1.0.1
+`
+
+func TestCheckGoSum(t *testing.T) {
+	tests := []struct {
+		name       string
+		goSum      string
+		modulesTxt string
+		wantCodes  map[string]int
+		wantSev    Severity
+	}{
+		{
+			name: "complete",
+			goSum: "example.com/direct v1.0.0 h1:a=\nexample.com/direct v1.0.0/go.mod h1:b=\n" +
+				"example.com/indirect v1.1.0 h1:c=\nexample.com/indirect v1.1.0/go.mod h1:d=\n" +
+				"example.com/fork v1.0.1 h1:e=\nexample.com/fork v1.0.1/go.mod h1:f=\n",
+			wantCodes: map[string]int{},
+			wantSev:   SeverityOK,
+		},
+		{
+			name: "indirect_zip_hash_missing",
+			goSum: "example.com/direct v1.0.0 h1:a=\nexample.com/direct v1.0.0/go.mod h1:b=\n" +
+				"example.com/indirect v1.1.0/go.mod h1:d=\n" +
+				"example.com/fork v1.0.1 h1:e=\nexample.com/fork v1.0.1/go.mod h1:f=\n",
+			wantCodes: map[string]int{"GO_SUM_ENTRY_MISSING": 1},
+			wantSev:   SeverityWarn,
+		},
+		{
+			name: "replacement_and_transitive_missing",
+			goSum: "example.com/direct v1.0.0 h1:a=\nexample.com/direct v1.0.0/go.mod h1:b=\n" +
+				"example.com/indirect v1.1.0 h1:c=\nexample.com/indirect v1.1.0/go.mod h1:d=\n",
+			modulesTxt: "# example.com/direct v1.0.0\n## explicit\nexample.com/direct\n" +
+				"# example.com/transitive v0.3.0\nexample.com/transitive\n",
+			wantCodes: map[string]int{"GO_SUM_ENTRY_MISSING": 4},
+			wantSev:   SeverityErr,
+		},
+		{
+			name:      "go_sum_missing",
+			wantCodes: map[string]int{"GO_SUM_MISSING": 1},
+			wantSev:   SeverityErr,
+		},
+		{
+			name:      "go_sum_malformed",
+			goSum:     "example.com/direct v1.0.0\n",
+			wantCodes: map[string]int{"GO_SUM_MALFORMED": 1},
+			wantSev:   SeverityErr,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, "go.mod"), sumGoMod)
+			if tc.goSum != "" {
+				writeFile(t, filepath.Join(dir, "go.sum"), tc.goSum)
+			}
+			if tc.modulesTxt != "" {
+				writeFile(t, filepath.Join(dir, "vendor", "modules.txt"), tc.modulesTxt)
+			}
+
+			issues := checkGoSum(dir, []byte(sumGoMod))
+
+			gotCodes := map[string]int{}
+			for _, issue := range issues {
+				gotCodes[issue.Code]++
+			}
+			if len(gotCodes) != len(tc.wantCodes) {
+				t.Fatalf("issues: got %+v, want codes %v", issues, tc.wantCodes)
+			}
+			for code, n := range tc.wantCodes {
+				if gotCodes[code] != n {
+					t.Fatalf("issue %s: got %d, want %d (%+v)", code, gotCodes[code], n, issues)
+				}
+			}
+			if got := maxSeverity(issues); got != tc.wantSev {
+				t.Fatalf("severity: got %q, want %q", got, tc.wantSev)
+			}
+		})
+	}
+}
+
+func TestCheckGoSumNoRequirements(t *testing.T) {
+	dir := t.TempDir()
+	goMod := "module example.com/app\n\ngo 1.25\n"
+
+	if issues := checkGoSum(dir, []byte(goMod)); len(issues) != 0 {
+		t.Fatalf("issues: expected none, got %+v", issues)
+	}
+}
@@ -0,0 +1,130 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	packageNameRE   = regexp.MustCompile(`^[a-z0-9][a-z0-9._+-]*$`)
+	shortNameRE     = regexp.MustCompile(`~[0-9]+$`)
+	gopkgInSuffixRE = regexp.MustCompile(`\.v(0|[1-9][0-9]*)(-unstable)?$`)
+)
+
+var reservedNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// checkModulePath applies the go command's module path rules and reports
+// each violated rule under its own code.
+func checkModulePath(modulePath string) []Issue {
+	issue := func(severity Severity, code, format string, args ...any) Issue {
+		return Issue{
+			Severity: severity,
+			Code:     code,
+			Message:  fmt.Sprintf(format, args...),
+			Path:     modulePath,
+		}
+	}
+
+	if !utf8.ValidString(modulePath) {
+		return []Issue{issue(SeverityErr, "MODULE_PATH_INVALID_CHAR", "module path is not valid UTF-8")}
+	}
+
+	elems := strings.Split(modulePath, "/")
+	for _, elem := range elems {
+		if elem == "" {
+			return []Issue{issue(SeverityErr, "MODULE_PATH_EMPTY_ELEMENT",
+				"module path has a leading, trailing or doubled slash")}
+		}
+	}
+
+	var issues []Issue
+	for _, elem := range elems {
+		if bad := firstBadRune(elem); bad != 0 {
+			issues = append(issues, issue(SeverityErr, "MODULE_PATH_INVALID_CHAR",
+				"element %q contains disallowed character %q", elem, bad))
+			continue
+		}
+		if strings.HasPrefix(elem, ".") || strings.HasSuffix(elem, ".") {
+			issues = append(issues, issue(SeverityErr, "MODULE_PATH_DOT_ELEMENT",
+				"element %q begins or ends with a dot", elem))
+			continue
+		}
+		if isReservedName(elem) {
+			issues = append(issues, issue(SeverityErr, "MODULE_PATH_RESERVED_NAME",
+				"element %q is a reserved file name", elem))
+		}
+	}
+
+	domain := elems[0]
+	switch {
+	case strings.ToLower(domain) != domain || strings.ContainsAny(domain, "_~"):
+		issues = append(issues, issue(SeverityErr, "MODULE_PATH_BAD_DOMAIN",
+			"leading element %q may contain only lower-case letters, digits, dots and dashes", domain))
+	case strings.HasPrefix(domain, "-"):
+		issues = append(issues, issue(SeverityErr, "MODULE_PATH_BAD_DOMAIN",
+			"leading element %q begins with a dash", domain))
+	case !strings.Contains(domain, "."):
+		issues = append(issues, issue(SeverityWarn, "MODULE_PATH_NO_DOMAIN",
+			"leading element %q is not a domain name; the module cannot be fetched by others", domain))
+	}
+
+	issues = append(issues, checkMajorSuffix(modulePath, elems, issue)...)
+
+	return issues
+}
+
+func checkMajorSuffix(modulePath string, elems []string, issue func(Severity, string, string, ...any) Issue) []Issue {
+	last := elems[len(elems)-1]
+
+	if elems[0] == "gopkg.in" {
+		if len(elems) < 2 || !gopkgInSuffixRE.MatchString(last) {
+			return []Issue{issue(SeverityErr, "MODULE_PATH_GOPKGIN_SUFFIX",
+				"gopkg.in path %q must end in .vN", modulePath)}
+		}
+		return nil
+	}
+
+	if len(elems) < 2 || len(last) < 2 || last[0] != 'v' {
+		return nil
+	}
+	digits := last[1:]
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return nil
+		}
+	}
+	if digits == "0" || digits == "1" || strings.HasPrefix(digits, "0") {
+		return []Issue{issue(SeverityErr, "MODULE_PATH_BAD_MAJOR_SUFFIX",
+			"major version suffix /%s is invalid; only /v2 and above are allowed", last)}
+	}
+
+	return nil
+}
+
+func firstBadRune(elem string) rune {
+	for _, r := range elem {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		case r == '-' || r == '.' || r == '_' || r == '~':
+		default:
+			return r
+		}
+	}
+	return 0
+}
+
+func isReservedName(elem string) bool {
+	base, _, _ := strings.Cut(elem, ".")
+	for _, name := range reservedNames {
+		if strings.EqualFold(base, name) {
+			return true
+		}
+	}
+	return shortNameRE.MatchString(base)
+}
@@ -0,0 +1,79 @@
+package validate
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckModulePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantCode string
+		wantSev  Severity
+	}{
+		{path: "github.com/example/project", wantSev: SeverityOK},
+		{path: "github.com/BurntSushi/toml", wantSev: SeverityOK},
+		{path: "github.com/example/project/v2", wantSev: SeverityOK},
+		{path: "gopkg.in/yaml.v3", wantSev: SeverityOK},
+		{path: "gopkg.in/check.v1", wantSev: SeverityOK},
+		{path: "example.com/a_b~c", wantSev: SeverityOK},
+		{path: "myapp", wantCode: "MODULE_PATH_NO_DOMAIN", wantSev: SeverityWarn},
+		{path: "github.com/example/my project", wantCode: "MODULE_PATH_INVALID_CHAR", wantSev: SeverityErr},
+		{path: "github.com/example/proj@ect", wantCode: "MODULE_PATH_INVALID_CHAR", wantSev: SeverityErr},
+		{path: "github.com//project", wantCode: "MODULE_PATH_EMPTY_ELEMENT", wantSev: SeverityErr},
+		{path: "github.com/project/", wantCode: "MODULE_PATH_EMPTY_ELEMENT", wantSev: SeverityErr},
+		{path: "github.com/.hidden", wantCode: "MODULE_PATH_DOT_ELEMENT", wantSev: SeverityErr},
+		{path: "github.com/trailing.", wantCode: "MODULE_PATH_DOT_ELEMENT", wantSev: SeverityErr},
+		{path: "GitHub.com/example/project", wantCode: "MODULE_PATH_BAD_DOMAIN", wantSev: SeverityErr},
+		{path: "-github.com/example", wantCode: "MODULE_PATH_BAD_DOMAIN", wantSev: SeverityErr},
+		{path: "example.com/con", wantCode: "MODULE_PATH_RESERVED_NAME", wantSev: SeverityErr},
+		{path: "example.com/ABCDEF~1", wantCode: "MODULE_PATH_RESERVED_NAME", wantSev: SeverityErr},
+		{path: "github.com/example/project/v1", wantCode: "MODULE_PATH_BAD_MAJOR_SUFFIX", wantSev: SeverityErr},
+		{path: "github.com/example/project/v0", wantCode: "MODULE_PATH_BAD_MAJOR_SUFFIX", wantSev: SeverityErr},
+		{path: "github.com/example/project/v02", wantCode: "MODULE_PATH_BAD_MAJOR_SUFFIX", wantSev: SeverityErr},
+		{path: "gopkg.in/yaml", wantCode: "MODULE_PATH_GOPKGIN_SUFFIX", wantSev: SeverityErr},
+		{path: "gopkg.in/yaml/v3", wantCode: "MODULE_PATH_GOPKGIN_SUFFIX", wantSev: SeverityErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			issues := checkModulePath(tt.path)
+			if got := maxSeverity(issues); got != tt.wantSev {
+				t.Fatalf("severity: got %q, want %q (%+v)", got, tt.wantSev, issues)
+			}
+			if tt.wantCode != "" && findIssue(issues, tt.wantCode) == nil {
+				t.Fatalf("expected %s issue, got %+v", tt.wantCode, issues)
+			}
+		})
+	}
+}
+
+func TestCheckNameGopkgIn(t *testing.T) {
+	name, issue := checkName("gopkg.in/yaml.v3")
+	if issue != nil {
+		t.Fatalf("unexpected issue: %+v", issue)
+	}
+	if name != "yaml" {
+		t.Fatalf("name: got %q, want %q", name, "yaml")
+	}
+}
+
+func TestCheckNameInvalid(t *testing.T) {
+	_, issue := checkName("example.com/my project")
+	if issue == nil || issue.Code != "NAME_INVALID" {
+		t.Fatalf("issue: got %+v, want NAME_INVALID", issue)
+	}
+}
+
+func TestPreQuotedModulePath(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module \"example.com/my project\"\n\ngo 1.25\n")
+
+	report := Pre(dir)
+	if report.Verdict != SeverityErr {
+		t.Fatalf("verdict: got %q, want %q", report.Verdict, SeverityErr)
+	}
+	if findIssue(report.Issues, "MODULE_PATH_INVALID_CHAR") == nil {
+		t.Fatalf("expected MODULE_PATH_INVALID_CHAR issue, got %+v", report.Issues)
+	}
+}
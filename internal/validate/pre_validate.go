@@ -13,11 +13,24 @@ func Pre(dir string) Report {
 		issues = append(issues, *issue)
 	}
 
+	if modulePath != "" {
+		issues = append(issues, checkModulePath(modulePath)...)
+	}
+
 	name, issue := checkName(modulePath)
 	if issue != nil {
 		issues = append(issues, *issue)
 	}
 
+	issues = append(issues, checkGoSum(dir, goModFile)...)
+	issues = append(issues, checkGearConflicts(dir, name)...)
+	issues = append(issues, checkCgoRequires(dir, modulePath, name)...)
+
+	issue = CheckWorkspace(dir)
+	if issue != nil {
+		issues = append(issues, *issue)
+	}
+
 	issue = checkWritable(dir)
 	if issue != nil {
 		issues = append(issues, *issue)
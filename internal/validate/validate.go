@@ -1,6 +1,7 @@
 package validate
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"syscall"
@@ -87,6 +88,14 @@ func checkName(modulePath string) (name string, issue *Issue) {
 			Path:     modulePath,
 		}
 	}
+	if !packageNameRE.MatchString(name) {
+		return name, &Issue{
+			Severity: SeverityErr,
+			Code:     "NAME_INVALID",
+			Message:  fmt.Sprintf("derived package name %q is not a valid package name", name),
+			Path:     modulePath,
+		}
+	}
 
 	return name, nil
 }
@@ -136,6 +145,28 @@ func checkWritable(dir string) *Issue {
 	}
 }
 
+func CheckWorkspace(dir string) *Issue {
+	ws, err := inspect.LoadWorkspace(dir)
+	if err != nil {
+		return &Issue{
+			Severity: SeverityErr,
+			Code:     "GO_WORK_MALFORMED",
+			Message:  err.Error(),
+			Path:     dir,
+		}
+	}
+	if ws == nil {
+		return nil
+	}
+
+	return &Issue{
+		Severity: SeverityWarn,
+		Code:     "GO_WORK_ACTIVE",
+		Message:  "dir is inside a go.work workspace; vendoring must run with GOWORK=off",
+		Path:     ws.Path,
+	}
+}
+
 func maxSeverity(issues []Issue) Severity {
 	maxSeverity := SeverityOK
 	for _, issue := range issues {
@@ -188,3 +188,29 @@ func TestPreMissingGoMod(t *testing.T) {
 		t.Fatalf("expected GO_MOD_MISSING issue")
 	}
 }
+
+func TestCheckWorkspace(t *testing.T) {
+	t.Setenv("GOWORK", "")
+
+	dir := t.TempDir()
+	writeGoMod(t, dir, "github.com/example/project")
+	if issue := CheckWorkspace(dir); issue != nil {
+		t.Fatalf("unexpected issue: %+v", issue)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.work"), []byte("go 1.25\n\nuse .\n"), 0o644); err != nil {
+		t.Fatalf("write go.work: %v", err)
+	}
+	issue := CheckWorkspace(dir)
+	if issue == nil || issue.Code != "GO_WORK_ACTIVE" {
+		t.Fatalf("issue: got %+v, want GO_WORK_ACTIVE", issue)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.work"), []byte("module x\n"), 0o644); err != nil {
+		t.Fatalf("write go.work: %v", err)
+	}
+	issue = CheckWorkspace(dir)
+	if issue == nil || issue.Code != "GO_WORK_MALFORMED" {
+		t.Fatalf("issue: got %+v, want GO_WORK_MALFORMED", issue)
+	}
+}
@@ -0,0 +1,189 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/reservation-v/vlang/internal/dirhash"
+	"github.com/reservation-v/vlang/internal/gosum"
+	"github.com/reservation-v/vlang/internal/modcache"
+	"github.com/reservation-v/vlang/internal/modfile"
+	"github.com/reservation-v/vlang/internal/modulestxt"
+)
+
+// checkVendorIntegrity compares every vendored module tree with the upstream
+// content pinned in go.sum. A vendored tree that is a full copy of the module
+// is hashed directly; otherwise each vendored file is compared with the copy
+// in the module cache, after the cache copy itself is checked against go.sum.
+func checkVendorIntegrity(dir string, modFile *modfile.File, vendored *modulestxt.File) []Issue {
+	goSumPath := filepath.Join(dir, "go.sum")
+	data, readErr := os.ReadFile(goSumPath)
+	if os.IsNotExist(readErr) {
+		// The pre stage reports a missing go.sum; without one there is
+		// nothing to verify against.
+		return nil
+	}
+	if readErr != nil {
+		return []Issue{{
+			Severity: SeverityErr,
+			Code:     "GO_SUM_READ_FAILED",
+			Message:  fmt.Sprintf("go.sum cannot be read; vendored code cannot be verified: %v", readErr),
+			Path:     goSumPath,
+		}}
+	}
+	sums, parseErr := gosum.Parse(data)
+	if parseErr != nil {
+		return []Issue{{
+			Severity: SeverityErr,
+			Code:     "GO_SUM_MALFORMED",
+			Message:  fmt.Sprintf("vendored code cannot be verified: %v", parseErr),
+			Path:     goSumPath,
+		}}
+	}
+
+	vendorDir := filepath.Join(dir, "vendor")
+	owned, walkIssue := vendoredFiles(vendorDir, vendored)
+	if walkIssue != nil {
+		return []Issue{*walkIssue}
+	}
+
+	cache := modcache.Dir()
+	var issues []Issue
+	for _, mod := range vendored.Modules {
+		if mod.Version == "" {
+			continue
+		}
+		srcPath, srcVersion := mod.Path, mod.Version
+		if rep := modFile.ReplacementFor(mod.Path, mod.Version); rep != nil {
+			if rep.NewVersion == "" {
+				continue
+			}
+			srcPath, srcVersion = rep.NewPath, rep.NewVersion
+		}
+
+		want := sums.Hash(srcPath, srcVersion, false)
+		files := owned[mod.Path]
+		if want == "" || len(files) == 0 {
+			continue
+		}
+
+		issues = append(issues, verifyVendoredModule(vendorDir, cache, mod.Path, srcPath, srcVersion, want, files)...)
+	}
+
+	return issues
+}
+
+func verifyVendoredModule(vendorDir, cache, modPath, srcPath, srcVersion, want string, files []string) []Issue {
+	modDir := filepath.Join(vendorDir, filepath.FromSlash(modPath))
+	prefix := srcPath + "@" + srcVersion
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, prefix+"/"+file)
+	}
+	got, hashErr := dirhash.Hash1(names, func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(modDir, filepath.FromSlash(strings.TrimPrefix(name, prefix+"/"))))
+	})
+	if hashErr == nil && got == want {
+		return nil
+	}
+
+	cacheDir := modcache.ModuleDir(cache, srcPath, srcVersion)
+	if _, statErr := os.Stat(cacheDir); statErr != nil {
+		return []Issue{{
+			Severity: SeverityWarn,
+			Code:     "VENDOR_UNVERIFIABLE",
+			Message:  fmt.Sprintf("%s is not in the module cache; vendored code cannot be verified", prefix),
+			Path:     modDir,
+		}}
+	}
+
+	cacheHash, cacheErr := dirhash.HashDir(cacheDir, prefix)
+	if cacheErr != nil || cacheHash != want {
+		return []Issue{{
+			Severity: SeverityWarn,
+			Code:     "VENDOR_UNVERIFIABLE",
+			Message:  fmt.Sprintf("module cache copy of %s does not match go.sum", prefix),
+			Path:     cacheDir,
+		}}
+	}
+
+	var issues []Issue
+	for _, file := range files {
+		vendored := filepath.Join(modDir, filepath.FromSlash(file))
+		upstream, upstreamErr := os.ReadFile(filepath.Join(cacheDir, filepath.FromSlash(file)))
+		if upstreamErr != nil {
+			issues = append(issues, Issue{
+				Severity: SeverityErr,
+				Code:     "VENDOR_TAMPERED",
+				Message:  fmt.Sprintf("%s is not part of %s", file, prefix),
+				Path:     vendored,
+			})
+			continue
+		}
+		local, localErr := os.ReadFile(vendored)
+		if localErr != nil || !bytes.Equal(local, upstream) {
+			issues = append(issues, Issue{
+				Severity: SeverityErr,
+				Code:     "VENDOR_TAMPERED",
+				Message:  fmt.Sprintf("%s differs from %s", file, prefix),
+				Path:     vendored,
+			})
+		}
+	}
+
+	return issues
+}
+
+// vendoredFiles maps each vendored module path to the slash-separated paths,
+// relative to the module root, of the files that belong to it. A file belongs
+// to the module with the longest path that is a prefix of its location.
+func vendoredFiles(vendorDir string, vendored *modulestxt.File) (map[string][]string, *Issue) {
+	paths := make([]string, 0, len(vendored.Modules))
+	for _, mod := range vendored.Modules {
+		if mod.Version != "" {
+			paths = append(paths, mod.Path)
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool { return len(paths[i]) > len(paths[j]) })
+
+	owned := make(map[string][]string)
+	walkErr := filepath.WalkDir(vendorDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(vendorDir, path)
+		if relErr != nil {
+			return relErr
+		}
+		rel = filepath.ToSlash(rel)
+
+		for _, modPath := range paths {
+			if strings.HasPrefix(rel, modPath+"/") {
+				owned[modPath] = append(owned[modPath], strings.TrimPrefix(rel, modPath+"/"))
+				break
+			}
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return nil, &Issue{
+			Severity: SeverityErr,
+			Code:     "VENDOR_WALK_FAILED",
+			Message:  walkErr.Error(),
+			Path:     vendorDir,
+		}
+	}
+
+	return owned, nil
+}
@@ -0,0 +1,134 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reservation-v/vlang/internal/dirhash"
+	"github.com/reservation-v/vlang/internal/modcache"
+)
+
+const integrityGoMod = "module example.com/app\n\ngo 1.25\n\nrequire example.com/Lib v1.0.0\n"
+
+const integrityModulesTxt = "# example.com/Lib v1.0.0\n## explicit\nexample.com/Lib\n"
+
+func setupIntegrity(t *testing.T, inCache bool) (dir string) {
+	t.Helper()
+
+	cache := t.TempDir()
+	t.Setenv("GOMODCACHE", cache)
+
+	upstream := map[string]string{
+		"LICENSE":      "MIT\n",
+		"lib.go":       "package lib\n",
+		"lib_test.go":  "package lib\n",
+		"docs/a.md":    "docs\n",
+		"sub/other.go": "package sub\n",
+	}
+	modDir := modcache.ModuleDir(cache, "example.com/Lib", "v1.0.0")
+	for name, content := range upstream {
+		writeFile(t, filepath.Join(modDir, filepath.FromSlash(name)), content)
+	}
+	want, err := dirhash.HashDir(modDir, "example.com/Lib@v1.0.0")
+	if err != nil {
+		t.Fatalf("hash cache dir: %v", err)
+	}
+	if !inCache {
+		if err := os.RemoveAll(modDir); err != nil {
+			t.Fatalf("remove cache dir: %v", err)
+		}
+	}
+
+	dir = t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), integrityGoMod)
+	writeFile(t, filepath.Join(dir, "go.sum"),
+		"example.com/Lib v1.0.0 "+want+"\nexample.com/Lib v1.0.0/go.mod h1:x=\n")
+	writeFile(t, filepath.Join(dir, "vendor", "modules.txt"), integrityModulesTxt)
+	writeFile(t, filepath.Join(dir, "vendor", "example.com", "Lib", "LICENSE"), upstream["LICENSE"])
+	writeFile(t, filepath.Join(dir, "vendor", "example.com", "Lib", "lib.go"), upstream["lib.go"])
+
+	return dir
+}
+
+func TestVendorIntegrityOK(t *testing.T) {
+	dir := setupIntegrity(t, true)
+
+	report := Vendor(dir)
+	if report.Verdict != SeverityOK {
+		t.Fatalf("verdict: got %q, want %q (%+v)", report.Verdict, SeverityOK, report.Issues)
+	}
+}
+
+func TestVendorIntegrityTampered(t *testing.T) {
+	dir := setupIntegrity(t, true)
+	writeFile(t, filepath.Join(dir, "vendor", "example.com", "Lib", "lib.go"), "package lib\n\nvar Patched = true\n")
+	writeFile(t, filepath.Join(dir, "vendor", "example.com", "Lib", "extra.go"), "package lib\n")
+
+	report := Vendor(dir)
+	if report.Verdict != SeverityErr {
+		t.Fatalf("verdict: got %q, want %q", report.Verdict, SeverityErr)
+	}
+	tampered := 0
+	for _, issue := range report.Issues {
+		if issue.Code == "VENDOR_TAMPERED" {
+			tampered++
+		}
+	}
+	if tampered != 2 {
+		t.Fatalf("VENDOR_TAMPERED issues: got %d, want 2 (%+v)", tampered, report.Issues)
+	}
+}
+
+func TestVendorIntegrityNotInCache(t *testing.T) {
+	dir := setupIntegrity(t, false)
+
+	report := Vendor(dir)
+	if report.Verdict != SeverityWarn {
+		t.Fatalf("verdict: got %q, want %q (%+v)", report.Verdict, SeverityWarn, report.Issues)
+	}
+	if findIssue(report.Issues, "VENDOR_UNVERIFIABLE") == nil {
+		t.Fatalf("expected VENDOR_UNVERIFIABLE issue, got %+v", report.Issues)
+	}
+}
+
+func TestVendorIntegrityBadGoSum(t *testing.T) {
+	dir := setupIntegrity(t, true)
+	writeFile(t, filepath.Join(dir, "go.sum"), "example.com/Lib v1.0.0\n")
+
+	report := Vendor(dir)
+	if report.Verdict != SeverityErr || findIssue(report.Issues, "GO_SUM_MALFORMED") == nil {
+		t.Fatalf("expected GO_SUM_MALFORMED error, got %q %+v", report.Verdict, report.Issues)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "go.sum")); err != nil {
+		t.Fatalf("remove go.sum: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "go.sum"), 0o755); err != nil {
+		t.Fatalf("mkdir go.sum: %v", err)
+	}
+	report = Vendor(dir)
+	if findIssue(report.Issues, "GO_SUM_READ_FAILED") == nil {
+		t.Fatalf("expected GO_SUM_READ_FAILED issue, got %+v", report.Issues)
+	}
+}
+
+func TestVendorIntegrityFullCopy(t *testing.T) {
+	t.Setenv("GOMODCACHE", t.TempDir())
+
+	dir := t.TempDir()
+	modDir := filepath.Join(dir, "vendor", "example.com", "Lib")
+	writeFile(t, filepath.Join(modDir, "lib.go"), "package lib\n")
+	want, err := dirhash.HashDir(modDir, "example.com/Lib@v1.0.0")
+	if err != nil {
+		t.Fatalf("hash vendored dir: %v", err)
+	}
+	writeFile(t, filepath.Join(dir, "go.mod"), integrityGoMod)
+	writeFile(t, filepath.Join(dir, "go.sum"), "example.com/Lib v1.0.0 "+want+"\n")
+	writeFile(t, filepath.Join(dir, "vendor", "modules.txt"), integrityModulesTxt)
+
+	report := Vendor(dir)
+	if report.Verdict != SeverityOK {
+		t.Fatalf("verdict: got %q, want %q (%+v)", report.Verdict, SeverityOK, report.Issues)
+	}
+}
@@ -0,0 +1,199 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/reservation-v/vlang/internal/modfile"
+	"github.com/reservation-v/vlang/internal/modulestxt"
+)
+
+func Vendor(dir string) Report {
+	issues := make([]Issue, 0, 4)
+
+	goModFile, issue := checkGoMod(dir)
+	if issue != nil {
+		issues = append(issues, *issue)
+	}
+
+	modulePath, issue := checkModule(goModFile)
+	if issue != nil {
+		issues = append(issues, *issue)
+	}
+
+	name, issue := checkName(modulePath)
+	if issue != nil {
+		issues = append(issues, *issue)
+	}
+
+	if goModFile != nil {
+		issues = append(issues, checkVendor(dir, goModFile)...)
+	}
+
+	return Report{
+		Stage:      "vendor",
+		Verdict:    maxSeverity(issues),
+		Issues:     issues,
+		ModulePath: modulePath,
+		Name:       name,
+	}
+}
+
+func checkVendor(dir string, goModData []byte) []Issue {
+	goModPath := filepath.Join(dir, "go.mod")
+	modFile, parseErr := modfile.Parse(goModData)
+	if parseErr != nil {
+		return []Issue{{
+			Severity: SeverityErr,
+			Code:     "GO_MOD_MALFORMED",
+			Message:  parseErr.Error(),
+			Path:     goModPath,
+		}}
+	}
+
+	vendorDir := filepath.Join(dir, "vendor")
+	modulesTxtPath := filepath.Join(vendorDir, "modules.txt")
+	data, readErr := os.ReadFile(modulesTxtPath)
+	if os.IsNotExist(readErr) {
+		if len(modFile.Require) == 0 {
+			return nil
+		}
+		return []Issue{{
+			Severity: SeverityErr,
+			Code:     "MODULES_TXT_MISSING",
+			Message:  "vendor/modules.txt is missing but go.mod has requirements",
+			Path:     modulesTxtPath,
+		}}
+	}
+	if readErr != nil {
+		return []Issue{{
+			Severity: SeverityErr,
+			Code:     "MODULES_TXT_READ_FAILED",
+			Message:  "vendor/modules.txt cannot be read",
+			Path:     modulesTxtPath,
+		}}
+	}
+
+	vendored, vendorErr := modulestxt.Parse(data)
+	if vendorErr != nil {
+		return []Issue{{
+			Severity: SeverityErr,
+			Code:     "MODULES_TXT_MALFORMED",
+			Message:  vendorErr.Error(),
+			Path:     modulesTxtPath,
+		}}
+	}
+
+	var issues []Issue
+	issues = append(issues, checkVendorRequirements(modFile, vendored, modulesTxtPath)...)
+	issues = append(issues, checkVendorReplacements(modFile, vendored, modulesTxtPath)...)
+	issues = append(issues, checkVendorPackages(vendorDir, vendored)...)
+	issues = append(issues, checkVendorIntegrity(dir, modFile, vendored)...)
+
+	return issues
+}
+
+func checkVendorRequirements(modFile *modfile.File, vendored *modulestxt.File, path string) []Issue {
+	var issues []Issue
+
+	required := make(map[string]bool, len(modFile.Require))
+	for _, req := range modFile.Require {
+		required[req.Path] = true
+
+		mod := vendored.Module(req.Path)
+		switch {
+		case mod == nil:
+			issues = append(issues, Issue{
+				Severity: SeverityErr,
+				Code:     "VENDOR_MODULE_MISSING",
+				Message:  fmt.Sprintf("%s@%s is required in go.mod but not vendored", req.Path, req.Version),
+				Path:     path,
+			})
+		case mod.Version != req.Version:
+			issues = append(issues, Issue{
+				Severity: SeverityErr,
+				Code:     "VENDOR_MODULE_STALE",
+				Message:  fmt.Sprintf("%s is required at %s but vendored at %s", req.Path, req.Version, mod.Version),
+				Path:     path,
+			})
+		case !mod.Explicit:
+			issues = append(issues, Issue{
+				Severity: SeverityErr,
+				Code:     "VENDOR_NOT_EXPLICIT",
+				Message:  fmt.Sprintf("%s is required in go.mod but not marked ## explicit", req.Path),
+				Path:     path,
+			})
+		}
+	}
+
+	for _, mod := range vendored.Modules {
+		if mod.Explicit && !required[mod.Path] {
+			issues = append(issues, Issue{
+				Severity: SeverityErr,
+				Code:     "VENDOR_MODULE_STALE",
+				Message:  fmt.Sprintf("%s@%s is marked ## explicit but not required in go.mod", mod.Path, mod.Version),
+				Path:     path,
+			})
+		}
+	}
+
+	return issues
+}
+
+func checkVendorReplacements(modFile *modfile.File, vendored *modulestxt.File, path string) []Issue {
+	var issues []Issue
+
+	for _, mod := range vendored.Modules {
+		if mod.Version == "" {
+			continue
+		}
+
+		var wantPath, wantVersion string
+		if rep := modFile.ReplacementFor(mod.Path, mod.Version); rep != nil {
+			wantPath, wantVersion = rep.NewPath, rep.NewVersion
+		}
+		if mod.NewPath == wantPath && mod.NewVersion == wantVersion {
+			continue
+		}
+
+		issues = append(issues, Issue{
+			Severity: SeverityErr,
+			Code:     "VENDOR_REPLACE_MISMATCH",
+			Message: fmt.Sprintf("%s@%s is vendored with replacement %q but go.mod has %q",
+				mod.Path, mod.Version, joinReplacement(mod.NewPath, mod.NewVersion), joinReplacement(wantPath, wantVersion)),
+			Path: path,
+		})
+	}
+
+	return issues
+}
+
+func checkVendorPackages(vendorDir string, vendored *modulestxt.File) []Issue {
+	var issues []Issue
+
+	for _, mod := range vendored.Modules {
+		for _, pkg := range mod.Packages {
+			pkgDir := filepath.Join(vendorDir, filepath.FromSlash(pkg))
+			info, err := os.Stat(pkgDir)
+			if err == nil && info.IsDir() {
+				continue
+			}
+			issues = append(issues, Issue{
+				Severity: SeverityErr,
+				Code:     "VENDOR_PACKAGE_MISSING",
+				Message:  fmt.Sprintf("package %s is listed in modules.txt but missing on disk", pkg),
+				Path:     pkgDir,
+			})
+		}
+	}
+
+	return issues
+}
+
+func joinReplacement(path, version string) string {
+	if version == "" {
+		return path
+	}
+	return path + " " + version
+}
@@ -0,0 +1,144 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const vendorGoMod = `module example.com/app
+
+go 1.25
+
+require (
+	example.com/a v1.0.0
+	example.com/b v1.1.0 // indirect
+)
+
+replace example.com/b => example.com/fork v1.1.1
+`
+
+const vendorModulesTxt = `# example.com/a v1.0.0
+## explicit; go 1.21
+example.com/a
+# example.com/b v1.1.0 => example.com/fork v1.1.1
+## explicit; go 1.20
+example.com/b/pkg
+# example.com/c v0.2.0
+example.com/c
+`
+
+func writeVendorTree(t *testing.T, dir, goMod, modulesTxt string, pkgs ...string) {
+	t.Helper()
+
+	writeFile(t, filepath.Join(dir, "go.mod"), goMod)
+	writeFile(t, filepath.Join(dir, "vendor", "modules.txt"), modulesTxt)
+	for _, pkg := range pkgs {
+		if err := os.MkdirAll(filepath.Join(dir, "vendor", filepath.FromSlash(pkg)), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", pkg, err)
+		}
+	}
+}
+
+func TestVendorOK(t *testing.T) {
+	dir := t.TempDir()
+	writeVendorTree(t, dir, vendorGoMod, vendorModulesTxt, "example.com/a", "example.com/b/pkg", "example.com/c")
+
+	report := Vendor(dir)
+	if report.Stage != "vendor" {
+		t.Fatalf("stage: got %q, want %q", report.Stage, "vendor")
+	}
+	if report.Verdict != SeverityOK {
+		t.Fatalf("verdict: got %q, want %q (%+v)", report.Verdict, SeverityOK, report.Issues)
+	}
+	if report.Name != "app" {
+		t.Fatalf("name: got %q, want %q", report.Name, "app")
+	}
+}
+
+func TestVendorIssues(t *testing.T) {
+	tests := []struct {
+		name       string
+		goMod      string
+		modulesTxt string
+		pkgs       []string
+		wantCode   string
+	}{
+		{
+			name:       "module_missing",
+			goMod:      vendorGoMod + "\nrequire example.com/d v0.1.0\n",
+			modulesTxt: vendorModulesTxt,
+			pkgs:       []string{"example.com/a", "example.com/b/pkg", "example.com/c"},
+			wantCode:   "VENDOR_MODULE_MISSING",
+		},
+		{
+			name:       "version_stale",
+			goMod:      "module example.com/app\n\ngo 1.25\n\nrequire example.com/a v1.0.1\n",
+			modulesTxt: "# example.com/a v1.0.0\n## explicit\nexample.com/a\n",
+			pkgs:       []string{"example.com/a"},
+			wantCode:   "VENDOR_MODULE_STALE",
+		},
+		{
+			name:       "explicit_not_required",
+			goMod:      "module example.com/app\n\ngo 1.25\n",
+			modulesTxt: "# example.com/a v1.0.0\n## explicit\nexample.com/a\n",
+			pkgs:       []string{"example.com/a"},
+			wantCode:   "VENDOR_MODULE_STALE",
+		},
+		{
+			name:       "not_explicit",
+			goMod:      "module example.com/app\n\ngo 1.25\n\nrequire example.com/a v1.0.0\n",
+			modulesTxt: "# example.com/a v1.0.0\nexample.com/a\n",
+			pkgs:       []string{"example.com/a"},
+			wantCode:   "VENDOR_NOT_EXPLICIT",
+		},
+		{
+			name:       "replacement_changed",
+			goMod:      "module example.com/app\n\ngo 1.25\n\nrequire example.com/a v1.0.0\n\nreplace example.com/a => ../a\n",
+			modulesTxt: "# example.com/a v1.0.0\n## explicit\nexample.com/a\n",
+			pkgs:       []string{"example.com/a"},
+			wantCode:   "VENDOR_REPLACE_MISMATCH",
+		},
+		{
+			name:       "package_dir_missing",
+			goMod:      vendorGoMod,
+			modulesTxt: vendorModulesTxt,
+			pkgs:       []string{"example.com/a", "example.com/b/pkg"},
+			wantCode:   "VENDOR_PACKAGE_MISSING",
+		},
+		{
+			name:       "modules_txt_malformed",
+			goMod:      vendorGoMod,
+			modulesTxt: "example.com/a\n",
+			wantCode:   "MODULES_TXT_MALFORMED",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeVendorTree(t, dir, tc.goMod, tc.modulesTxt, tc.pkgs...)
+
+			report := Vendor(dir)
+			if report.Verdict != SeverityErr {
+				t.Fatalf("verdict: got %q, want %q", report.Verdict, SeverityErr)
+			}
+			if len(report.Issues) != 1 {
+				t.Fatalf("issues: got %+v, want only %s", report.Issues, tc.wantCode)
+			}
+			if findIssue(report.Issues, tc.wantCode) == nil {
+				t.Fatalf("expected %s issue, got %+v", tc.wantCode, report.Issues)
+			}
+		})
+	}
+}
+
+func TestVendorModulesTxtMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), vendorGoMod)
+
+	report := Vendor(dir)
+	if findIssue(report.Issues, "MODULES_TXT_MISSING") == nil {
+		t.Fatalf("expected MODULES_TXT_MISSING issue, got %+v", report.Issues)
+	}
+}